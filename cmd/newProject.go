@@ -21,10 +21,16 @@
 package cmd
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
 	"github.com/lavrahq/cli/packages/fs"
+	"github.com/lavrahq/cli/packages/prompt"
 	"github.com/lavrahq/cli/packages/tmpl"
 	"github.com/lavrahq/cli/util"
 	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
 )
 
@@ -34,6 +40,27 @@ var flagNewProjectTrack bool
 // Stores the --template, -t flag
 var flagNewProjectTemplate string
 
+// Stores the --answers flag
+var flagNewProjectAnswers string
+
+// Stores the --set flags
+var flagNewProjectSet []string
+
+// Stores the --no-input flag
+var flagNewProjectNoInput bool
+
+// Stores the --replay flag
+var flagNewProjectReplay bool
+
+// Stores the --dry-run flag
+var flagNewProjectDryRun bool
+
+// Stores the --on-conflict flag
+var flagNewProjectOnConflict string
+
+// Stores the --trust flag
+var flagNewProjectTrust bool
+
 // projectsCreateCmd represents the projectsCreate command
 var newProjectCmd = &cobra.Command{
 	Use:   "project <dir=.>",
@@ -52,30 +79,147 @@ tracked and managed via the CLI.`,
 
 		setupProjDir := util.Spin("Configuring project directory")
 		projDir, _ := fs.MakeDirectory(rawDir)
-		if projDir.IsProject() {
+
+		// Replays regenerate an existing project in place.
+		if projDir.IsProject() && !flagNewProjectReplay {
 			cmdutil.ExitWithMessage("You cannot create a new project within a project root.")
 
 			return
 		}
 		setupProjDir.Done()
 
+		var record tmpl.Record
+		from := flagNewProjectTemplate
+		if flagNewProjectReplay {
+			if !tmpl.HasRecord(projDir) {
+				cmdutil.ExitWithMessage("There is no " + tmpl.RecordDirectory + "/" + tmpl.RecordFile + " to replay within the project directory.")
+			}
+
+			loaded, err := tmpl.LoadRecord(projDir)
+			cmdutil.CheckCommandError(err, "loading answers record")
+
+			record = loaded
+			if !cmd.Flags().Changed("template") {
+				from = record.Source()
+			}
+		}
+
+		if flagNewProjectOnConflict != "" && !tmpl.IsValidConflictPolicy(flagNewProjectOnConflict) {
+			cmdutil.ExitWithMessage("The --on-conflict policy must be one of overwrite, skip, prompt, backup or fail.")
+		}
+
+		// Replays only ask for the answers left out of the record, such as
+		// passwords, and answer them from `LAVRA_<NAME>` without a TTY.
+		noInput := flagNewProjectAnswers != "" || flagNewProjectNoInput || (flagNewProjectReplay && !util.IsInteractive())
+
 		configureTemplate := util.Spin("Configuring project template")
-		template := tmpl.Make(projDir, flagNewProjectTemplate)
+		template := tmpl.Make(projDir, from)
+		template.ConflictPolicy = flagNewProjectOnConflict
+		template.NoInput = noInput
 		configureTemplate.Done()
 
 		// Ensure template is fetched.
 		template.EnsureTemplateIsFetched()
 
+		// Replay against the recorded commits unless another template was given.
+		if flagNewProjectReplay && from == record.Source() {
+			if record.Commit != "" {
+				template.Checkout(record.Commit)
+			}
+
+			template.Pins = record.Pins()
+		}
+
 		// Reload the manifest once the template is fetched.
 		template = template.LoadManifest()
-		template.Prompt()
+
+		// Hooks never run during a dry run, and the commands the template
+		// needs are checked before anything is asked or expanded.
+		if !flagNewProjectDryRun {
+			template.EnsureChecksPass()
+			template.EnsureHooksTrusted(flagNewProjectTrust)
+		}
+
+		answers := make(prompt.AnswerMap)
+		if flagNewProjectReplay {
+			answers = record.Answers
+		}
+
+		if flagNewProjectAnswers != "" {
+			loaded, err := prompt.LoadAnswersFile(flagNewProjectAnswers)
+			cmdutil.CheckCommandError(err, "loading answers file")
+
+			answers = loaded
+		}
+
+		overrides, err := prompt.ParseSetValues(flagNewProjectSet)
+		cmdutil.CheckCommandError(err, "parsing --set values")
+		answers = answers.Merge(overrides)
+
+		if noInput {
+			_, err := template.Answer(answers)
+			cmdutil.CheckCommandError(err, "answering template questions")
+		} else {
+			template.Prompt(answers)
+		}
+
+		// Plan the copy against the project, render into a scratch
+		// directory and compare it with the project.
+		if flagNewProjectDryRun {
+			scratchDir, err := ioutil.TempDir("", "lavra-dry-run")
+			cmdutil.CheckCommandError(err, "creating temp directory")
+			defer os.RemoveAll(scratchDir)
+
+			scratch, _ := fs.MakeDirectory(scratchDir)
+			changes, err := template.DryRun(scratch)
+			cmdutil.CheckCommandError(err, "comparing expansion with project")
+
+			cmd.Println()
+			cmd.Print(tmpl.FileTree(projDir.Path, changes))
+
+			if failed := template.Conflicts.Failed(); len(failed) > 0 {
+				cmd.Println()
+				cmd.Println(fmt.Sprintf(" %s", aurora.Yellow("The expansion would fail, as the files marked `conflict: fail` already exist and differ from the template.")))
+			}
+
+			for _, change := range changes {
+				if change.Diff != "" {
+					cmd.Println()
+					cmd.Print(change.Diff)
+				}
+			}
+
+			return
+		}
 
 		// Copy the template files and dirs.
+		template.RunHooks(tmpl.HookPreCopy)
 		template.Copy()
+		template.RunHooks(tmpl.HookPostCopy)
 		cmd.Println()
 
 		// Fill the template files.
+		template.RunHooks(tmpl.HookPreFill)
 		template.Fill()
+		template.RunHooks(tmpl.HookPostFill)
+
+		// Record the answers so the expansion can be replayed.
+		template.WriteRecord()
+
+		if len(template.Conflicts.Conflicts) > 0 {
+			cmd.Println()
+			cmd.Println(" The following files already existed:")
+			cmd.Println(template.Conflicts.Summary())
+		}
+
+		// Show the template's next steps.
+		notes, err := template.RenderNotes()
+		cmdutil.CheckCommandError(err, "rendering template notes")
+
+		if notes != "" {
+			cmd.Println()
+			cmd.Println(notes)
+		}
 	},
 }
 
@@ -87,4 +231,25 @@ func init() {
 
 	// Allows specifying the template for the new project.
 	newProjectCmd.Flags().StringVarP(&flagNewProjectTemplate, "template", "t", "empty", "Specifies the template to use for the new project")
+
+	// Allows answering the template questions from a file, or stdin with `-`.
+	newProjectCmd.Flags().StringVarP(&flagNewProjectAnswers, "answers", "", "", "Answers the template questions from a YAML file, or stdin with -")
+
+	// Allows overriding individual answers.
+	newProjectCmd.Flags().StringArrayVarP(&flagNewProjectSet, "set", "", []string{}, "Sets an answer as Name=value, overriding the answers file")
+
+	// Allows running without any prompts.
+	newProjectCmd.Flags().BoolVarP(&flagNewProjectNoInput, "no-input", "", false, "Never prompt, using question defaults for anything not answered")
+
+	// Allows regenerating the project from its recorded answers.
+	newProjectCmd.Flags().BoolVarP(&flagNewProjectReplay, "replay", "", false, "Regenerates the project in place from the answers recorded in .lavra/answers.yml")
+
+	// Allows previewing the expansion without writing anything.
+	newProjectCmd.Flags().BoolVarP(&flagNewProjectDryRun, "dry-run", "", false, "Shows the files the expansion would create or change, with diffs, without writing them")
+
+	// Allows choosing what happens to existing files that differ from the template.
+	newProjectCmd.Flags().StringVarP(&flagNewProjectOnConflict, "on-conflict", "", "", "Policy for existing files: overwrite, skip, prompt, backup or fail (default prompt when asking on a TTY, fail otherwise)")
+
+	// Allows running template hooks without confirmation.
+	newProjectCmd.Flags().BoolVarP(&flagNewProjectTrust, "trust", "", false, "Trusts the template's hooks without asking for confirmation")
 }
@@ -60,6 +60,10 @@ func init() {
 	// will be global for your application.
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.lavra/config.yml)")
 
+	// Allows expanding templates from the cache without network access.
+	rootCmd.PersistentFlags().Bool("offline", false, "Uses cached templates without network access")
+	viper.BindPFlag("templates.offline", rootCmd.PersistentFlags().Lookup("offline"))
+
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
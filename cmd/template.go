@@ -0,0 +1,63 @@
+// Copyright © 2019 Scott Plunkett <plunkets@aeoss.io>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lavrahq/cli/packages/fs"
+	"github.com/lavrahq/cli/packages/tmpl"
+	"github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+// templateCmd represents the template command
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Allows managing templates and the projects generated from them.",
+	Long: `This command group allows managing project templates, as well as the
+projects that have been generated from a template.`,
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+}
+
+// makeLocalTemplate loads the manifest of the template being authored
+// within the given directory.
+func makeLocalTemplate(rawDir string) tmpl.Template {
+	templateDir, _ := fs.MakeDirectory(rawDir)
+
+	return tmpl.Make(fs.Directory{}, templateDir.Path).LoadManifest()
+}
+
+// printRegistryEntries lists the registry entries, one per line.
+func printRegistryEntries(cmd *cobra.Command, entries []tmpl.RegistryEntry) {
+	for _, entry := range entries {
+		cmd.Println(fmt.Sprintf(" %s %s", aurora.Bold(fmt.Sprintf("%-24s", entry.Name)), entry.Description))
+
+		if len(entry.Tags) > 0 {
+			cmd.Println(fmt.Sprintf(" %-24s %s", "", aurora.Gray(12, strings.Join(entry.Tags, ", "))))
+		}
+	}
+}
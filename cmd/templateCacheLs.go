@@ -0,0 +1,90 @@
+// Copyright © 2019 Scott Plunkett <plunkets@aeoss.io>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lavrahq/cli/packages/tmpl"
+	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+// templateCacheLsCmd represents the templateCacheLs command
+var templateCacheLsCmd = &cobra.Command{
+	Use:     "ls",
+	Short:   "Lists the cached templates.",
+	Long:    `The ls command lists every cached template, when it was fetched and its size on disk.`,
+	Aliases: []string{"list"},
+	Args:    cobra.NoArgs,
+	PreRun:  cmdutil.PreRun,
+	PostRun: cmdutil.PostRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := tmpl.ListCache()
+		cmdutil.CheckCommandError(err, "listing template cache")
+
+		if len(entries) == 0 {
+			cmd.Println("There are no cached templates.")
+
+			return
+		}
+
+		for _, entry := range entries {
+			source := entry.Source
+			if source == "" {
+				source = "(unknown)"
+			}
+
+			fetched := aurora.Gray(12, "fetched "+entry.Fetched.Format(time.RFC822))
+			if entry.Fresh() {
+				fetched = aurora.Green("fetched " + entry.Fetched.Format(time.RFC822))
+			}
+
+			cmd.Println(fmt.Sprintf(" %s %s", aurora.Bold(fmt.Sprintf("%-40s", source)), fetched))
+			cmd.Println(fmt.Sprintf(" %-40s %s, %s", "", formatSize(entry.Size), entry.Key))
+		}
+	},
+}
+
+// formatSize formats a number of bytes for display.
+func formatSize(size int64) string {
+	units := []string{"B", "KB", "MB", "GB"}
+	value := float64(size)
+
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%d %s", size, units[unit])
+	}
+
+	return fmt.Sprintf("%.1f %s", value, units[unit])
+}
+
+func init() {
+	templateCacheCmd.AddCommand(templateCacheLsCmd)
+}
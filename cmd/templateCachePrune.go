@@ -0,0 +1,67 @@
+// Copyright © 2019 Scott Plunkett <plunkets@aeoss.io>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lavrahq/cli/packages/tmpl"
+	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+// Stores the --older-than flag
+var flagTemplateCachePruneOlderThan time.Duration
+
+// templateCachePruneCmd represents the templateCachePrune command
+var templateCachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Removes cached templates that have not been fetched recently.",
+	Long: `The prune command removes every cached template that has not been fetched within the
+--older-than duration, 30 days by default.`,
+	Args:    cobra.NoArgs,
+	PreRun:  cmdutil.PreRun,
+	PostRun: cmdutil.PostRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		pruned, err := tmpl.PruneCache(flagTemplateCachePruneOlderThan)
+		cmdutil.CheckCommandError(err, "pruning template cache")
+
+		for _, entry := range pruned {
+			source := entry.Source
+			if source == "" {
+				source = entry.Key
+			}
+
+			cmd.Println(fmt.Sprintf(" - %s", source))
+		}
+
+		cmd.Println(fmt.Sprintf("Pruned %d cached template(s).", len(pruned)))
+	},
+}
+
+func init() {
+	templateCacheCmd.AddCommand(templateCachePruneCmd)
+
+	// Allows choosing how recently a template must have been fetched to be kept.
+	templateCachePruneCmd.Flags().DurationVarP(&flagTemplateCachePruneOlderThan, "older-than", "", 30*24*time.Hour, "Removes templates not fetched within the duration")
+}
@@ -0,0 +1,82 @@
+// Copyright © 2019 Scott Plunkett <plunkets@aeoss.io>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+
+package cmd
+
+import (
+	"github.com/lavrahq/cli/packages/fs"
+	"github.com/lavrahq/cli/packages/tmpl"
+	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+// templateCacheWarmCmd represents the templateCacheWarm command
+var templateCacheWarmCmd = &cobra.Command{
+	Use:   "warm [templates...]",
+	Short: "Fetches templates into the cache ahead of time.",
+	Long: `The warm command fetches the given templates, given like the -t flag of new project, into
+the cache so they can be used with --offline. Without any templates, every cached template
+is fetched again.`,
+	PreRun:  cmdutil.PreRun,
+	PostRun: cmdutil.PostRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		if tmpl.IsOffline() {
+			cmdutil.ExitWithMessage("Templates cannot be fetched while offline.")
+		}
+
+		sources := args
+		if len(sources) == 0 {
+			entries, err := tmpl.ListCache()
+			cmdutil.CheckCommandError(err, "listing template cache")
+
+			for _, entry := range entries {
+				if entry.Source != "" {
+					sources = append(sources, entry.Source)
+				}
+			}
+		}
+
+		if len(sources) == 0 {
+			cmdutil.ExitWithMessage("There are no cached templates to warm, give the templates to fetch.")
+		}
+
+		for _, source := range sources {
+			template := tmpl.Make(fs.Directory{}, source)
+			if template.Local {
+				cmd.Println("Skipping the local template " + source + ".")
+
+				continue
+			}
+
+			if template.Archive {
+				template.FetchArchive()
+
+				continue
+			}
+
+			template.FetchTemplate()
+		}
+	},
+}
+
+func init() {
+	templateCacheCmd.AddCommand(templateCacheWarmCmd)
+}
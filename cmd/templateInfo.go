@@ -0,0 +1,111 @@
+// Copyright © 2019 Scott Plunkett <plunkets@aeoss.io>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lavrahq/cli/packages/fs"
+	"github.com/lavrahq/cli/packages/tmpl"
+	"github.com/lavrahq/cli/util"
+	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+// templateInfoCmd represents the templateInfo command
+var templateInfoCmd = &cobra.Command{
+	Use:   "info <name>",
+	Short: "Shows the details and questions of a template.",
+	Long: `The info command fetches the template, given like the -t flag of new project, and shows
+its registry details along with the questions its manifest asks.`,
+	Args:    cobra.ExactArgs(1),
+	PreRun:  cmdutil.PreRun,
+	PostRun: cmdutil.PostRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := tmpl.SplitRef(args[0])
+		entry, listed, err := tmpl.FindRegistryEntry(name)
+		cmdutil.CheckCommandError(err, "loading template registries")
+
+		configureTemplate := util.Spin("Configuring template")
+		template := tmpl.Make(fs.Directory{}, args[0])
+		configureTemplate.Done()
+
+		template.EnsureTemplateIsFetched()
+		template = template.LoadManifest()
+		manifest := template.Manifest
+
+		cmd.Println()
+		cmd.Println(fmt.Sprintf(" %s", aurora.Bold(manifest.Name)))
+		if manifest.Description != "" {
+			cmd.Println(fmt.Sprintf(" %s", manifest.Description))
+		}
+		cmd.Println()
+
+		cmd.Println(fmt.Sprintf(" %-12s %s", "Source:", template.Source()))
+		if manifest.Author != "" {
+			cmd.Println(fmt.Sprintf(" %-12s %s", "Author:", manifest.Author))
+		}
+
+		if listed {
+			if len(entry.Tags) > 0 {
+				cmd.Println(fmt.Sprintf(" %-12s %s", "Tags:", strings.Join(entry.Tags, ", ")))
+			}
+
+			if len(entry.Versions) > 0 {
+				cmd.Println(fmt.Sprintf(" %-12s %s", "Versions:", strings.Join(entry.Versions, ", ")))
+			}
+		}
+
+		cmd.Println()
+		if len(manifest.Prompt.Questions) == 0 {
+			cmd.Println(" The template does not ask any questions.")
+
+			return
+		}
+
+		cmd.Println(" Questions:")
+		for _, question := range manifest.Prompt.Questions {
+			cmd.Println(fmt.Sprintf("   %s %s", aurora.Bold(fmt.Sprintf("%-16s", question.Name)), aurora.Gray(12, question.Type)))
+			if question.Options.Message != "" {
+				cmd.Println(fmt.Sprintf("   %-16s %s", "", question.Options.Message))
+			}
+
+			if len(question.Options.Options) > 0 {
+				cmd.Println(fmt.Sprintf("   %-16s options: %s", "", strings.Join(question.Options.Options, ", ")))
+			}
+
+			if question.Options.Default != "" {
+				cmd.Println(fmt.Sprintf("   %-16s default: %s", "", question.Options.Default))
+			}
+
+			if question.When != "" {
+				cmd.Println(fmt.Sprintf("   %-16s when: %s", "", question.When))
+			}
+		}
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templateInfoCmd)
+}
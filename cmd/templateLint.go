@@ -0,0 +1,70 @@
+// Copyright © 2019 Scott Plunkett <plunkets@aeoss.io>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+// templateLintCmd represents the templateLint command
+var templateLintCmd = &cobra.Command{
+	Use:   "lint <dir=.>",
+	Short: "Checks a template manifest for mistakes.",
+	Long: `The lint command validates the template.yml within the given directory, reporting
+unknown question types and transforms, when expressions that do not compile, copies
+from paths missing from the template/ directory, and fills of files that no copy
+entry writes into the project.`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRun:  cmdutil.PreRun,
+	PostRun: cmdutil.PostRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		var rawDir = "."
+		if len(args) != 0 {
+			rawDir = args[0]
+		}
+
+		template := makeLocalTemplate(rawDir)
+
+		issues := template.Lint()
+		if len(issues) == 0 {
+			cmd.Println(aurora.Green("No problems were found within the template."))
+
+			return
+		}
+
+		cmd.Println()
+		for _, issue := range issues {
+			cmd.Println(fmt.Sprintf(" %s %s", aurora.Red(issue.Field+":"), issue.Message))
+		}
+		cmd.Println()
+
+		cmdutil.ExitWithMessage(fmt.Sprintf("%d problem(s) were found within the template.", len(issues)))
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templateLintCmd)
+}
@@ -0,0 +1,60 @@
+// Copyright © 2019 Scott Plunkett <plunkets@aeoss.io>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+
+package cmd
+
+import (
+	"github.com/lavrahq/cli/packages/tmpl"
+	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+// templateListCmd represents the templateList command
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the templates within the configured registries.",
+	Long: `The list command lists every template within the registry indexes configured under
+templates.registries, along with their descriptions and tags.`,
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	PreRun:  cmdutil.PreRun,
+	PostRun: cmdutil.PostRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(tmpl.GetRegistries()) == 0 {
+			cmdutil.ExitWithMessage("There are no registries configured under templates.registries.")
+		}
+
+		entries, err := tmpl.LoadRegistries()
+		cmdutil.CheckCommandError(err, "loading template registries")
+
+		if len(entries) == 0 {
+			cmd.Println("The configured registries do not list any templates.")
+
+			return
+		}
+
+		printRegistryEntries(cmd, entries)
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templateListCmd)
+}
@@ -0,0 +1,79 @@
+// Copyright © 2019 Scott Plunkett <plunkets@aeoss.io>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/lavrahq/cli/packages/fs"
+	"github.com/lavrahq/cli/packages/tmpl"
+	"github.com/lavrahq/cli/util"
+	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+// Stores the --name flag
+var flagTemplateNewName string
+
+// templateNewCmd represents the templateNew command
+var templateNewCmd = &cobra.Command{
+	Use:   "new <dir=.>",
+	Short: "Scaffolds a new template at the specified directory. Defaults to current dir.",
+	Long: `The new command writes a template skeleton into the given directory: a template.yml
+asking for the project name, a template/ directory holding a README.md to fill, and a
+test case under tests/default that ` + "`lavra template test`" + ` checks the expansion against.`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRun:  cmdutil.PreRun,
+	PostRun: cmdutil.PostRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		var rawDir = "."
+		if len(args) != 0 {
+			rawDir = args[0]
+		}
+
+		templateDir, _ := fs.MakeDirectory(rawDir)
+		if templateDir.IsTemplate() {
+			cmdutil.ExitWithMessage("The directory already contains a template manifest.")
+		}
+
+		name := flagTemplateNewName
+		if name == "" {
+			name = filepath.Base(templateDir.Path)
+		}
+
+		scaffold := util.Spin("Scaffolding template")
+		err := tmpl.Scaffold(templateDir.Path, name)
+		if err != nil {
+			scaffold.Failed(err)
+
+			return
+		}
+		scaffold.Done()
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templateNewCmd)
+
+	// Allows naming the template, defaulting to the directory name.
+	templateNewCmd.Flags().StringVarP(&flagTemplateNewName, "name", "n", "", "The name of the template, defaults to the directory name")
+}
@@ -0,0 +1,112 @@
+// Copyright © 2019 Scott Plunkett <plunkets@aeoss.io>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/lavrahq/cli/packages/tmpl"
+	"github.com/lavrahq/cli/util"
+	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+// Stores the --format flag
+var flagTemplatePackFormat string
+
+// Stores the --output, -o flag
+var flagTemplatePackOutput string
+
+// Stores the --version flag
+var flagTemplatePackVersion string
+
+// templatePackCmd represents the templatePack command
+var templatePackCmd = &cobra.Command{
+	Use:   "pack <dir=.>",
+	Short: "Packs a template into an archive.",
+	Long: `The pack command packs every file of the template within the given directory, such as
+its template.yml, template/ directory and partials, into a tar.gz or tar.xz archive, along
+with the checksum of every file. Git metadata, the tests/ directory and the archive itself
+are left out. The archive is named after the template and its version, which defaults to
+the newest semver tag of the template's repository, and its checksum is written beside it
+as <archive>.sha256. Archives can be used with -t, either as a path or an http(s) URL, and
+archives downloaded over http(s) must publish the .sha256 file beside them.`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRun:  cmdutil.PreRun,
+	PostRun: cmdutil.PostRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		var rawDir = "."
+		if len(args) != 0 {
+			rawDir = args[0]
+		}
+
+		if !tmpl.IsValidArchiveFormat(flagTemplatePackFormat) {
+			cmdutil.ExitWithMessage("The --format must be one of tar.gz or tar.xz.")
+		}
+
+		template := makeLocalTemplate(rawDir)
+
+		version := flagTemplatePackVersion
+		if version == "" {
+			version = template.LatestVersion()
+		}
+
+		output := flagTemplatePackOutput
+		if output == "" {
+			name := template.Manifest.Name
+			if name == "" {
+				name = filepath.Base(template.TemplateDirectory.Path)
+			}
+
+			if version != "" {
+				name += "-" + version
+			}
+
+			output = name + "." + flagTemplatePackFormat
+		}
+
+		pack := util.Spin("Packing template into " + output)
+		sum, err := template.Pack(output, flagTemplatePackFormat)
+		if err != nil {
+			pack.Failed(err)
+
+			return
+		}
+		pack.Done()
+
+		cmd.Println(fmt.Sprintf(" sha256 %s", sum))
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templatePackCmd)
+
+	// Allows choosing the compression of the archive.
+	templatePackCmd.Flags().StringVarP(&flagTemplatePackFormat, "format", "", tmpl.ArchiveTarGz, "The archive format, either tar.gz or tar.xz")
+
+	// Allows choosing where the archive is written.
+	templatePackCmd.Flags().StringVarP(&flagTemplatePackOutput, "output", "o", "", "The archive to write, defaults to <name>-<version>.<format>")
+
+	// Allows versioning the archive when the template has no tags.
+	templatePackCmd.Flags().StringVarP(&flagTemplatePackVersion, "version", "", "", "The version of the template, defaults to its newest semver tag")
+}
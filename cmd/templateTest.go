@@ -0,0 +1,116 @@
+// Copyright © 2019 Scott Plunkett <plunkets@aeoss.io>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lavrahq/cli/packages/tmpl"
+	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+// Stores the --update flag
+var flagTemplateTestUpdate bool
+
+// templateTestCmd represents the templateTest command
+var templateTestCmd = &cobra.Command{
+	Use:   "test <dir=.>",
+	Short: "Tests a template against its golden output.",
+	Long: `The test command expands the template within the given directory once for every
+test case under tests/, using the case's answers.yml, and compares the result with the
+case's expected/ directory. Use --update to replace the expected output with the
+current expansion.`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRun:  cmdutil.PreRun,
+	PostRun: cmdutil.PostRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		var rawDir = "."
+		if len(args) != 0 {
+			rawDir = args[0]
+		}
+
+		template := makeLocalTemplate(rawDir)
+
+		cases, err := template.TestCases()
+		cmdutil.CheckCommandError(err, "listing test cases")
+
+		if len(cases) == 0 {
+			cmdutil.ExitWithMessage("The template has no test cases within " + tmpl.TestDirectory + "/.")
+		}
+
+		failed := 0
+		for _, test := range cases {
+			changes, err := template.Test(test, flagTemplateTestUpdate)
+			cmdutil.CheckCommandError(err, "running test case "+test.Name)
+
+			cmd.Println()
+			if flagTemplateTestUpdate {
+				cmd.Println(fmt.Sprintf(" %s %s", aurora.Yellow("updated"), test.Name))
+
+				continue
+			}
+
+			if len(changes) == 0 {
+				cmd.Println(fmt.Sprintf(" %s %s", aurora.Green("passed"), test.Name))
+
+				continue
+			}
+
+			failed++
+			cmd.Println(fmt.Sprintf(" %s %s", aurora.Red("failed"), test.Name))
+			for _, change := range changes {
+				cmd.Println(fmt.Sprintf("   %-10s %s", testChangeLabel(change.Action), change.File))
+
+				if change.Diff != "" {
+					cmd.Println()
+					cmd.Println(change.Diff)
+				}
+			}
+		}
+
+		if failed > 0 {
+			cmd.Println()
+			cmdutil.ExitWithMessage(fmt.Sprintf("%d of %d test case(s) failed.", failed, len(cases)))
+		}
+	},
+}
+
+// testChangeLabel describes how a file differs from the expected output.
+func testChangeLabel(action string) string {
+	switch action {
+	case tmpl.ChangeCreated:
+		return "unexpected"
+	case tmpl.ChangeDeleted:
+		return "missing"
+	}
+
+	return "differs"
+}
+
+func init() {
+	templateCmd.AddCommand(templateTestCmd)
+
+	// Allows regenerating the expected output of every test case.
+	templateTestCmd.Flags().BoolVarP(&flagTemplateTestUpdate, "update", "u", false, "Replaces the expected output with the current expansion")
+}
@@ -0,0 +1,186 @@
+// Copyright © 2019 Scott Plunkett <plunkets@aeoss.io>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/lavrahq/cli/packages/fs"
+	"github.com/lavrahq/cli/packages/prompt"
+	"github.com/lavrahq/cli/packages/tmpl"
+	"github.com/lavrahq/cli/util"
+	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+// Stores the --dry-run flag
+var flagTemplateUpgradeDryRun bool
+
+// Stores the --set flags
+var flagTemplateUpgradeSet []string
+
+// Stores the --to flag
+var flagTemplateUpgradeTo string
+
+// templateUpgradeCmd represents the templateUpgrade command
+var templateUpgradeCmd = &cobra.Command{
+	Use:   "upgrade <dir=.>",
+	Short: "Upgrades a generated project to the latest version of its template.",
+	Long: `The upgrade command renders the project's template at the commit it was generated
+from and at the latest commit, using the answers recorded in .lavra/answers.yml, and
+three-way merges the difference into the project. Changes that don't overlap with
+your own are applied, and overlapping changes are written with conflict markers.
+Templates it extends and includes are rendered at the commits recorded for them.
+Use --to to upgrade to another tag, branch, commit or semver range, which replaces
+the recorded ref.`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRun:  cmdutil.PreRun,
+	PostRun: cmdutil.PostRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		var rawDir = "."
+		if len(args) != 0 {
+			rawDir = args[0]
+		}
+
+		projDir, _ := fs.MakeDirectory(rawDir)
+		if !tmpl.HasRecord(projDir) {
+			cmdutil.ExitWithMessage("There is no " + tmpl.RecordDirectory + "/" + tmpl.RecordFile + " within the project directory to upgrade from.")
+		}
+
+		record, err := tmpl.LoadRecord(projDir)
+		cmdutil.CheckCommandError(err, "loading answers record")
+
+		if record.Commit == "" {
+			cmdutil.ExitWithMessage("The answers record does not include the commit the project was generated from.")
+		}
+
+		overrides, err := prompt.ParseSetValues(flagTemplateUpgradeSet)
+		cmdutil.CheckCommandError(err, "parsing --set values")
+		answers := record.Answers.Merge(overrides)
+
+		source := record.Source()
+		if cmd.Flags().Changed("to") {
+			source = tmpl.Template{From: record.From, Ref: flagTemplateUpgradeTo}.Source()
+		}
+
+		configureTemplate := util.Spin("Configuring project template")
+		template := tmpl.Make(projDir, source)
+		template.Refresh = true
+		configureTemplate.Done()
+
+		// Upgrades always fetch the latest version, unless offline.
+		template.EnsureTemplateIsFetched()
+
+		commit, err := template.Commit()
+		cmdutil.CheckCommandError(err, "resolving latest template commit")
+
+		// The templates it extends and includes are upgraded along with it.
+		latest := template.LoadManifest()
+
+		if commit == record.Commit && record.HasParts(latest.Parts) {
+			if template.Ref != record.Ref && !flagTemplateUpgradeDryRun {
+				record.Ref = template.Ref
+				err = record.Write(projDir)
+				cmdutil.CheckCommandError(err, "writing answers record")
+			}
+
+			cmd.Println("The project is already up to date with its template.")
+
+			return
+		}
+
+		baseDir, err := ioutil.TempDir("", "lavra-upgrade-base")
+		cmdutil.CheckCommandError(err, "creating temp directory")
+		defer os.RemoveAll(baseDir)
+
+		nextDir, err := ioutil.TempDir("", "lavra-upgrade-next")
+		cmdutil.CheckCommandError(err, "creating temp directory")
+		defer os.RemoveAll(nextDir)
+
+		base, _ := fs.MakeDirectory(baseDir)
+		next, _ := fs.MakeDirectory(nextDir)
+
+		pinned := template
+		pinned.Pins = record.Pins()
+
+		_, err = pinned.Render(record.Commit, base, answers)
+		cmdutil.CheckCommandError(err, "rendering template at "+record.Commit)
+
+		rendered, err := template.Render(commit, next, answers)
+		cmdutil.CheckCommandError(err, "rendering template at "+commit)
+
+		changes, err := tmpl.Upgrade(projDir, base, next, flagTemplateUpgradeDryRun)
+		cmdutil.CheckCommandError(err, "merging template changes")
+
+		cmd.Println()
+		conflicts := 0
+		for _, change := range changes {
+			action := aurora.Green(change.Action)
+			if change.Action == tmpl.ChangeConflict || change.Action == tmpl.ChangeKept {
+				action = aurora.Red(change.Action)
+			}
+
+			if change.Action == tmpl.ChangeConflict {
+				conflicts++
+			}
+
+			cmd.Println(fmt.Sprintf(" %-10s %s", action, change.File))
+
+			if flagTemplateUpgradeDryRun && change.Diff != "" {
+				cmd.Println()
+				cmd.Println(change.Diff)
+			}
+		}
+
+		if len(changes) == 0 {
+			cmd.Println(" No project files are affected by the template changes.")
+		}
+
+		if flagTemplateUpgradeDryRun {
+			return
+		}
+
+		rendered.Directory = projDir
+		rendered.WriteRecord()
+
+		if conflicts > 0 {
+			cmd.Println()
+			cmd.Println(fmt.Sprintf(" %d file(s) have conflicts, resolve the conflict markers before committing.", conflicts))
+		}
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templateUpgradeCmd)
+
+	// Allows previewing the upgrade without writing anything.
+	templateUpgradeCmd.Flags().BoolVarP(&flagTemplateUpgradeDryRun, "dry-run", "", false, "Shows the changes the upgrade would make without writing them")
+
+	// Allows overriding recorded answers, such as passwords that are never recorded.
+	templateUpgradeCmd.Flags().StringArrayVarP(&flagTemplateUpgradeSet, "set", "", []string{}, "Sets an answer as Name=value, overriding the recorded answers")
+
+	// Allows upgrading to another ref than the one recorded.
+	templateUpgradeCmd.Flags().StringVarP(&flagTemplateUpgradeTo, "to", "", "", "Upgrades to a tag, branch, commit or semver range, replacing the recorded ref")
+}
@@ -25,6 +25,7 @@ import (
 	"os"
 	"text/tabwriter"
 
+	"github.com/lavrahq/cli/packages/funcs"
 	"github.com/lavrahq/cli/version"
 	"github.com/spf13/cobra"
 )
@@ -52,7 +53,8 @@ to quickly create a Cobra application.`,
 		fmt.Fprintf(w, "\n %s\t%s", "Commit:", version.GitCommit)
 		fmt.Fprintf(w, "\n %s\t%s", "Branch:", version.GitBranch)
 		fmt.Fprintf(w, "\n %s\t%s", "Date:", version.BuildDate)
-		fmt.Fprintf(w, "\n %s\t%s\n", "Version:", version.Version)
+		fmt.Fprintf(w, "\n %s\t%s", "Version:", version.Version)
+		fmt.Fprintf(w, "\n %s\t%d\n", "Functions:", funcs.Version)
 		fmt.Fprintln(w)
 
 		if version.IsDevelopment() {
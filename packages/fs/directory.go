@@ -8,6 +8,16 @@ import (
 	"path/filepath"
 )
 
+// TemplateManifestFiles are the template manifest file names, in the
+// order they are looked for.
+var TemplateManifestFiles = []string{
+	"template.yml",
+	"template.yaml",
+	"template.json",
+	"template.toml",
+	"template.hcl",
+}
+
 // Directory is an instance of the directory manipulation utility.
 type Directory struct {
 	Path string
@@ -56,14 +66,9 @@ func (dir Directory) IsProject() bool {
 }
 
 // IsTemplate returns true/false depending on whether the directory
-// is a template directory.
+// is a template directory, holding a manifest in any format.
 func (dir Directory) IsTemplate() bool {
-	filePath := path.Join(dir.Path, "template.yml")
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return false
-	}
-
-	return true
+	return dir.TemplatePath() != ""
 }
 
 // ReadFile reads the file at the path specified, returning the bytes
@@ -74,7 +79,7 @@ func (dir Directory) ReadFile(file string) ([]byte, error) {
 	return ioutil.ReadFile(absPath)
 }
 
-// ReadTemplateManifest r
+// ReadTemplateManifest reads the manifest of a template directory.
 func (dir Directory) ReadTemplateManifest() ([]byte, error) {
 	if !dir.IsTemplate() {
 		return []byte{}, errors.New("not a template directory")
@@ -93,11 +98,15 @@ func (dir Directory) ProjectPath() string {
 	return ""
 }
 
-// TemplatePath returns the expected path of the project.yml file
-// or an empty string if the template.yml file was not found.
+// TemplatePath returns the path of the first template manifest found
+// within the directory, trying each of TemplateManifestFiles, or an
+// empty string if no manifest was found.
 func (dir Directory) TemplatePath() string {
-	if dir.IsProject() {
-		return path.Join(dir.Path, "template.yml")
+	for _, file := range TemplateManifestFiles {
+		filePath := path.Join(dir.Path, file)
+		if _, err := os.Stat(filePath); err == nil {
+			return filePath
+		}
 	}
 
 	return ""
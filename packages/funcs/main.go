@@ -0,0 +1,306 @@
+// Package funcs provides the function library available to every
+// template rendered by the CLI, such as `fill` files, templated paths
+// and hook settings.
+//
+// Version 1 of the library provides:
+//
+//	upper, lower, title, trim       change the case of, or trim, a string
+//	camel, pascal, snake, kebab     convert a string between naming styles
+//	slug                            make a URL-safe slug of a string
+//	replace OLD NEW S               replace every OLD within S with NEW
+//	quote                           wrap a string in double quotes
+//	split SEP S, join SEP LIST      split and join strings
+//	contains SUBSTR S               check if S contains SUBSTR
+//	default DEFAULT VALUE           use DEFAULT when VALUE is empty
+//	indent N S, nindent N S         indent every line of S by N spaces
+//	toYaml, toJson                  encode a value as YAML or JSON
+//	uuid                            generate a random v4 UUID
+//	randAlphaNum N                  generate a random alphanumeric string
+//	sha256                          hex encoded SHA-256 of a string
+//	now, date FORMAT TIME           the current time, formatted with Go layouts
+//	env NAME                        read an environment variable
+//
+// Templates rendered from a template repository also have
+// `include "partials/x.tpl" .`, which renders another file from the
+// template repository.
+package funcs
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/gosimple/slug"
+	"gopkg.in/yaml.v2"
+)
+
+// Version is the version of the function library. It changes whenever
+// a function is added or changes behavior, and templates needing a
+// newer library set it as their manifest's `minFuncsVersion`.
+const Version = 1
+
+// alphaNumeric holds the characters used by randAlphaNum.
+const alphaNumeric = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Map returns the function library as a template.FuncMap.
+func Map() template.FuncMap {
+	return template.FuncMap{
+		"upper":        strings.ToUpper,
+		"lower":        strings.ToLower,
+		"title":        strings.Title,
+		"trim":         strings.TrimSpace,
+		"camel":        Camel,
+		"pascal":       Pascal,
+		"snake":        Snake,
+		"kebab":        Kebab,
+		"slug":         slug.Make,
+		"replace":      replace,
+		"quote":        quote,
+		"split":        split,
+		"join":         join,
+		"contains":     contains,
+		"default":      defaultValue,
+		"indent":       indent,
+		"nindent":      nindent,
+		"toYaml":       toYaml,
+		"toJson":       toJSON,
+		"uuid":         uuid,
+		"randAlphaNum": randAlphaNum,
+		"sha256":       sha256Sum,
+		"now":          time.Now,
+		"date":         date,
+		"env":          os.Getenv,
+	}
+}
+
+// Words splits a string into its words, breaking on separators and
+// changes of case, so that `HTTPServer_name` becomes `HTTP`, `Server`
+// and `name`.
+func Words(s string) []string {
+	var words []string
+	var word []rune
+
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+
+			continue
+		}
+
+		if len(word) > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+
+		word = append(word, r)
+	}
+
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	return words
+}
+
+// capitalize upper cases the first letter and lower cases the rest.
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToUpper(runes[0])
+
+	return string(runes)
+}
+
+// Pascal converts a string to PascalCase.
+func Pascal(s string) string {
+	var out strings.Builder
+
+	for _, word := range Words(s) {
+		out.WriteString(capitalize(word))
+	}
+
+	return out.String()
+}
+
+// Camel converts a string to camelCase.
+func Camel(s string) string {
+	words := Words(s)
+	if len(words) == 0 {
+		return ""
+	}
+
+	return strings.ToLower(words[0]) + Pascal(strings.Join(words[1:], " "))
+}
+
+// Snake converts a string to snake_case.
+func Snake(s string) string {
+	return strings.ToLower(strings.Join(Words(s), "_"))
+}
+
+// Kebab converts a string to kebab-case.
+func Kebab(s string) string {
+	return strings.ToLower(strings.Join(Words(s), "-"))
+}
+
+func replace(old string, new string, s string) string {
+	return strings.Replace(s, old, new, -1)
+}
+
+func quote(s interface{}) string {
+	return fmt.Sprintf("%q", fmt.Sprint(s))
+}
+
+func split(sep string, s string) []string {
+	return strings.Split(s, sep)
+}
+
+func join(sep string, list interface{}) string {
+	value := reflect.ValueOf(list)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return fmt.Sprint(list)
+	}
+
+	var items []string
+	for i := 0; i < value.Len(); i++ {
+		items = append(items, fmt.Sprint(value.Index(i).Interface()))
+	}
+
+	return strings.Join(items, sep)
+}
+
+func contains(substr string, s string) bool {
+	return strings.Contains(s, substr)
+}
+
+// IsEmpty returns true for nil and zero values, as well as empty
+// strings, slices and maps.
+func IsEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+
+	return reflect.DeepEqual(value, reflect.Zero(v.Type()).Interface())
+}
+
+func defaultValue(fallback interface{}, value ...interface{}) interface{} {
+	if len(value) == 0 || IsEmpty(value[0]) {
+		return fallback
+	}
+
+	return value[0]
+}
+
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+
+	return pad + strings.Replace(s, "\n", "\n"+pad, -1)
+}
+
+func nindent(spaces int, s string) string {
+	return "\n" + indent(spaces, s)
+}
+
+func toYaml(value interface{}) (string, error) {
+	out, err := yaml.Marshal(value)
+
+	return strings.TrimSuffix(string(out), "\n"), err
+}
+
+// jsonable converts the map[interface{}]interface{} values produced by
+// yaml.v2 into values encoding/json can encode.
+func jsonable(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{})
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = jsonable(item)
+		}
+
+		return converted
+	case map[string]interface{}:
+		converted := make(map[string]interface{})
+		for key, item := range v {
+			converted[key] = jsonable(item)
+		}
+
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = jsonable(item)
+		}
+
+		return converted
+	}
+
+	return value
+}
+
+func toJSON(value interface{}) (string, error) {
+	out, err := json.Marshal(jsonable(value))
+
+	return string(out), err
+}
+
+func uuid() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+func randAlphaNum(length int) (string, error) {
+	out := make([]byte, length)
+	max := big.NewInt(int64(len(alphaNumeric)))
+
+	for i := range out {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+
+		out[i] = alphaNumeric[n.Int64()]
+	}
+
+	return string(out), nil
+}
+
+func sha256Sum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func date(layout string, t time.Time) string {
+	return t.Format(layout)
+}
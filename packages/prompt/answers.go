@@ -0,0 +1,346 @@
+package prompt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2/core"
+	"github.com/lavrahq/cli/packages/when"
+	"github.com/lavrahq/cli/util"
+	"gopkg.in/yaml.v2"
+)
+
+// matchNonAlphaNumeric matches runs of characters not allowed within
+// environment variable names.
+var matchNonAlphaNumeric = regexp.MustCompile("[^A-Za-z0-9]+")
+
+// EnvName returns the `LAVRA_<NAME>` environment variable the named
+// answer is given to hooks as, and read from when it is not recorded.
+func EnvName(name string) string {
+	return "LAVRA_" + strings.ToUpper(strings.Trim(matchNonAlphaNumeric.ReplaceAllString(name, "_"), "_"))
+}
+
+// IsRecorded returns true if answers to the question are recorded, so
+// that they can be replayed. Secrets such as Password answers are not.
+func (question Question) IsRecorded() bool {
+	return question.Type != "Password"
+}
+
+// MissingAnswersError is returned when answers are provided
+// non-interactively and required questions were left unanswered.
+type MissingAnswersError struct {
+	Prompt  string
+	Missing []string
+	Invalid []string
+}
+
+// Error lists every missing and invalid answer.
+func (err MissingAnswersError) Error() string {
+	var lines []string
+
+	for _, name := range err.Missing {
+		lines = append(lines, fmt.Sprintf("  - %s: an answer is required", name))
+	}
+
+	for _, invalid := range err.Invalid {
+		lines = append(lines, fmt.Sprintf("  - %s", invalid))
+	}
+
+	return fmt.Sprintf("unable to answer %s questions:\n%s", err.Prompt, strings.Join(lines, "\n"))
+}
+
+// LoadAnswersFile reads an AnswerMap from a YAML (or JSON) file. The
+// file `-` reads the answers from stdin.
+func LoadAnswersFile(file string) (AnswerMap, error) {
+	var bytes []byte
+	var err error
+
+	if file == "-" {
+		bytes, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		bytes, err = ioutil.ReadFile(file)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	answers := make(AnswerMap)
+	if err := yaml.Unmarshal(bytes, &answers); err != nil {
+		return nil, err
+	}
+
+	return answers, nil
+}
+
+// ParseSetValues parses `Name=value` pairs into an AnswerMap.
+func ParseSetValues(values []string) (AnswerMap, error) {
+	answers := make(AnswerMap)
+
+	for _, value := range values {
+		kv := strings.SplitN(value, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("expected Name=value, got `%s`", value)
+		}
+
+		answers[kv[0]] = kv[1]
+	}
+
+	return answers, nil
+}
+
+// Merge returns a new AnswerMap with the overrides applied on top of
+// the answers.
+func (answers AnswerMap) Merge(overrides AnswerMap) AnswerMap {
+	merged := make(AnswerMap)
+
+	for key, value := range answers {
+		merged[key] = value
+	}
+
+	for key, value := range overrides {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// HasDefault returns true if the question provides a default answer.
+func (question Question) HasDefault() bool {
+	return question.Options.Default != "" || question.Type == "Confirm"
+}
+
+// DefaultAnswer returns the answer used when the question is not asked,
+// matching what survey would return when the default is accepted.
+func (question Question) DefaultAnswer() (interface{}, error) {
+	if question.Type == "Confirm" && question.Options.Default == "" {
+		return true, nil
+	}
+
+	if question.Type == "Select" && question.Options.Default == "" && len(question.Options.Options) > 0 {
+		return core.OptionAnswer{Value: question.Options.Options[0], Index: 0}, nil
+	}
+
+	if question.Type == "MultiSelect" && question.Options.Default == "" {
+		return []core.OptionAnswer{}, nil
+	}
+
+	return question.Coerce(question.Options.Default)
+}
+
+// Coerce converts a provided answer into the same type survey would
+// return when asking the question interactively.
+func (question Question) Coerce(value interface{}) (interface{}, error) {
+	switch question.Type {
+	case "Confirm":
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+
+		str := fmt.Sprintf("%v", value)
+		if str == "" {
+			return false, nil
+		}
+
+		return strconv.ParseBool(str)
+	case "Select":
+		return question.findOption(fmt.Sprintf("%v", value))
+	case "MultiSelect":
+		var values []string
+
+		switch v := value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				values = append(values, fmt.Sprintf("%v", item))
+			}
+		case []string:
+			values = v
+		default:
+			for _, item := range strings.Split(fmt.Sprintf("%v", v), ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					values = append(values, item)
+				}
+			}
+		}
+
+		selected := []core.OptionAnswer{}
+		for _, item := range values {
+			option, err := question.findOption(item)
+			if err != nil {
+				return nil, err
+			}
+
+			selected = append(selected, option)
+		}
+
+		return selected, nil
+	}
+
+	if IsTypedPromptType(question.Type) {
+		return question.coerceTyped(value)
+	}
+
+	if value == nil {
+		return "", nil
+	}
+
+	return fmt.Sprintf("%v", value), nil
+}
+
+// findOption returns the OptionAnswer matching the value.
+func (question Question) findOption(value string) (core.OptionAnswer, error) {
+	for i, option := range question.Options.Options {
+		if option == value {
+			return core.OptionAnswer{Value: option, Index: i}, nil
+		}
+	}
+
+	return core.OptionAnswer{}, fmt.Errorf("`%s` is not one of: %s", value, strings.Join(question.Options.Options, ", "))
+}
+
+// Provide answers the questions from the provided answers rather than
+// asking them. Answers that are never recorded, such as passwords, can
+// also be given as `LAVRA_<NAME>` environment variables. Questions
+// without a provided answer fall back to their default. Each answer is
+// still validated and transformed.
+func (p Prompt) Provide(provided AnswerMap) (AnswerMap, error) {
+	if err := p.CheckTransforms(); err != nil {
+		return Answers[p.Name], err
+	}
+
+	missing := MissingAnswersError{Prompt: p.Name}
+
+	if Answers[p.Name] == nil {
+		Answers[p.Name] = make(AnswerMap)
+	}
+
+	for _, e := range p.Questions {
+		env := WhenEnvironment{
+			Answers: p.scope(),
+			Env:     util.GetEnvMap(),
+		}
+
+		if len(missing.Missing) > 0 || len(missing.Invalid) > 0 {
+			// earlier answers may be missing from the environment, so
+			// questions whose `when` cannot be evaluated are left out
+			if asked, err := when.Check(e.When, env); err != nil || !asked {
+				continue
+			}
+		} else if !when.True(e.When, env) {
+			continue
+		}
+
+		e, err := e.WithDefault(p.scope())
+		if err != nil {
+			missing.Invalid = append(missing.Invalid, fmt.Sprintf("%s: %s", e.Name, err.Error()))
+
+			continue
+		}
+
+		if e.Type == "Group" {
+			raw, ok := provided[e.Name]
+			items, err := p.provideGroup(e, raw, ok)
+			if err != nil {
+				missing.Invalid = append(missing.Invalid, fmt.Sprintf("%s: %s", e.Name, err.Error()))
+
+				continue
+			}
+
+			p.writeGroup(e, items)
+
+			continue
+		}
+
+		var value interface{}
+
+		if raw, ok := provided[e.Name]; ok {
+			value, err = e.Coerce(raw)
+		} else if raw, ok := os.LookupEnv(EnvName(e.Name)); ok && !e.IsRecorded() {
+			value, err = e.Coerce(raw)
+		} else if e.HasDefault() || !e.Validate.Required {
+			value, err = e.DefaultAnswer()
+		} else if !e.IsRecorded() {
+			missing.Invalid = append(missing.Invalid, fmt.Sprintf("%s: an answer is required, given with --set or %s", e.Name, EnvName(e.Name)))
+
+			continue
+		} else {
+			missing.Missing = append(missing.Missing, e.Name)
+
+			continue
+		}
+
+		if err == nil {
+			err = e.CheckValidWith(value, p.scope())
+		}
+
+		if err != nil {
+			missing.Invalid = append(missing.Invalid, fmt.Sprintf("%s: %s", e.Name, err.Error()))
+
+			continue
+		}
+
+		answer := &Answer{prompt: p, name: e.Name, question: e}
+		if err := answer.WriteAnswer(e.Name, value); err != nil {
+			missing.Invalid = append(missing.Invalid, fmt.Sprintf("%s: %s", e.Name, err.Error()))
+		}
+	}
+
+	if len(missing.Missing) > 0 || len(missing.Invalid) > 0 {
+		return Answers[p.Name], missing
+	}
+
+	return Answers[p.Name], p.Compute()
+}
+
+// Plain converts an answer returned by survey back into the plain value
+// accepted by Coerce, so that answers can be written to disk.
+func (question Question) Plain(value interface{}) interface{} {
+	switch v := value.(type) {
+	case core.OptionAnswer:
+		return v.Value
+	case []core.OptionAnswer:
+		values := []string{}
+		for _, option := range v {
+			values = append(values, option.Value)
+		}
+
+		return values
+	case []AnswerMap:
+		items := []AnswerMap{}
+		for _, item := range v {
+			items = append(items, recordable(question.Questions, item))
+		}
+
+		return items
+	}
+
+	return question.plainTyped(value)
+}
+
+// Recordable returns the raw answers given to the Prompt in their plain
+// form, leaving out secrets such as Password answers.
+func (p Prompt) Recordable() AnswerMap {
+	return recordable(p.Questions, Answers[p.Name])
+}
+
+// recordable returns the raw answers to the questions in their plain
+// form, leaving out answers that are never recorded.
+func recordable(questions []Question, answers AnswerMap) AnswerMap {
+	recorded := make(AnswerMap)
+
+	for _, e := range questions {
+		if !e.IsRecorded() {
+			continue
+		}
+
+		if raw, ok := answers["Raw"+e.Name]; ok {
+			recorded[e.Name] = e.Plain(raw)
+		}
+	}
+
+	return recorded
+}
@@ -0,0 +1,130 @@
+package prompt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/lavrahq/cli/packages/funcs"
+	"github.com/lavrahq/cli/packages/when"
+	"github.com/lavrahq/cli/util"
+)
+
+// Computed is an answer that is never asked. Its value is taken from an
+// `expr` expression or a `template` over the answers given before it.
+type Computed struct {
+	Name     string `yaml:"name"`
+	Expr     string `yaml:"expr"`
+	Template string `yaml:"template"`
+	When     string `yaml:"when"`
+}
+
+// IsTemplated returns true if the text contains template actions.
+func IsTemplated(text string) bool {
+	return strings.Contains(text, "{{")
+}
+
+// ParseText parses the text as a Go template with the function library.
+// Referring to a missing answer is an error, rather than rendering
+// `<no value>`.
+func ParseText(text string) (*template.Template, error) {
+	return template.New("").Option("missingkey=error").Funcs(funcs.Map()).Parse(text)
+}
+
+// RenderText renders the text as a Go template with the function
+// library, against the answers and the environment. Only the answers
+// given so far are in scope, so referring to a later answer, or to one
+// skipped by its `when`, fails. Text without template actions is
+// returned as-is.
+func RenderText(text string, answers AnswerMap) (string, error) {
+	if !IsTemplated(text) {
+		return text, nil
+	}
+
+	tmpl, err := ParseText(text)
+	if err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	err = tmpl.Execute(&out, WhenEnvironment{
+		Answers: answers,
+		Env:     util.GetEnvMap(),
+	})
+
+	return out.String(), err
+}
+
+// WithDefault returns the question with its default rendered against
+// the answers given before it, so defaults can build on earlier
+// answers, such as `{{ .Answers.Name | slug }}-db`.
+func (question Question) WithDefault(answers AnswerMap) (Question, error) {
+	rendered, err := RenderText(question.Options.Default, answers)
+	if err != nil {
+		return question, fmt.Errorf("rendering the default: %s", err)
+	}
+
+	question.Options.Default = rendered
+
+	return question, nil
+}
+
+// Value returns the computed answer's value.
+func (c Computed) Value(answers AnswerMap) (interface{}, error) {
+	if c.Expr != "" {
+		value, err := when.Eval(c.Expr, WhenEnvironment{
+			Answers: answers,
+			Env:     util.GetEnvMap(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("evaluating %s: %s", c.Name, strings.TrimSpace(err.Error()))
+		}
+
+		return value, nil
+	}
+
+	value, err := RenderText(c.Template, answers)
+	if err != nil {
+		return nil, fmt.Errorf("rendering %s: %s", c.Name, err)
+	}
+
+	return value, nil
+}
+
+// Compute stores the Prompt's computed answers within the answers, in
+// order, once every question is answered. Each computed answer can use
+// the computed answers before it.
+func (p Prompt) Compute() error {
+	if Answers[p.Name] == nil {
+		Answers[p.Name] = make(AnswerMap)
+	}
+
+	for _, c := range p.Computed {
+		if c.Name == "" {
+			return errors.New("a computed answer has no name")
+		}
+
+		asked, err := when.Check(c.When, WhenEnvironment{
+			Answers: Answers[p.Name],
+			Env:     util.GetEnvMap(),
+		})
+		if err != nil {
+			return fmt.Errorf("evaluating when of %s: %s", c.Name, strings.TrimSpace(err.Error()))
+		}
+
+		if !asked {
+			continue
+		}
+
+		value, err := c.Value(Answers[p.Name])
+		if err != nil {
+			return err
+		}
+
+		Answers[p.Name][c.Name] = value
+	}
+
+	return nil
+}
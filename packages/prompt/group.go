@@ -0,0 +1,243 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/lavrahq/cli/packages/when"
+	"github.com/lavrahq/cli/util"
+	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/logrusorgru/aurora"
+)
+
+// Repeat modes of a Group question. A `loop` asks whether to add
+// another item after each item, while a `count` asks for the number of
+// items given by the question's `count` expression.
+const (
+	RepeatLoop  = "loop"
+	RepeatCount = "count"
+)
+
+// IsValidRepeat checks that the given repeat mode is valid.
+func IsValidRepeat(repeat string) bool {
+	return repeat == "" || repeat == RepeatLoop || repeat == RepeatCount
+}
+
+// groupLabel returns the name items of the Group question are shown as.
+func (question Question) groupLabel() string {
+	if question.Options.Message != "" {
+		return question.Options.Message
+	}
+
+	return question.Name
+}
+
+// minItems returns the fewest items the Group question accepts.
+func (question Question) minItems() int {
+	if question.Validate.Required && question.Validate.MinItems == 0 {
+		return 1
+	}
+
+	return question.Validate.MinItems
+}
+
+// groupCount returns the number of items to ask for, evaluating the
+// `count` expression against the answers given before the Group
+// question. A loop returns -1.
+func (question Question) groupCount(answers AnswerMap) (int, error) {
+	if question.Repeat != RepeatCount {
+		return -1, nil
+	}
+
+	value, err := when.Eval(question.Count, WhenEnvironment{
+		Answers: answers,
+		Env:     util.GetEnvMap(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("evaluating count: %s", strings.TrimSpace(err.Error()))
+	}
+
+	number, ok := answerNumber(value)
+	if !ok || number < 0 {
+		return 0, fmt.Errorf("the count `%s` is not a number of items", question.Count)
+	}
+
+	return int(number), nil
+}
+
+// itemPrompt returns the Prompt answering a single item of the Group
+// question. Its answers start empty, with the answers given before the
+// group as its parent answers, so nested questions can build on them
+// without an answer skipped by its `when` taking an earlier value.
+func (p Prompt) itemPrompt(e Question, index int) Prompt {
+	name := fmt.Sprintf("%s.%s[%d]", p.Name, e.Name, index)
+	Answers[name] = make(AnswerMap)
+
+	return Prompt{Name: name, Questions: e.Questions, parent: p.scope()}
+}
+
+// groupItem picks the answers to the Group's own questions out of the
+// item Prompt's answers.
+func (question Question) groupItem(answers AnswerMap) AnswerMap {
+	item := make(AnswerMap)
+
+	for _, nested := range question.Questions {
+		for _, key := range []string{nested.Name, "Raw" + nested.Name} {
+			if value, ok := answers[key]; ok {
+				item[key] = value
+			}
+		}
+	}
+
+	return item
+}
+
+// groupItems converts a provided answer into the list of item answers.
+func groupItems(value interface{}) ([]AnswerMap, error) {
+	var list []interface{}
+
+	switch v := value.(type) {
+	case []AnswerMap:
+		return v, nil
+	case []interface{}:
+		list = v
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("expected a list of items, got `%v`", value)
+	}
+
+	items := []AnswerMap{}
+	for _, raw := range list {
+		item := make(AnswerMap)
+
+		switch v := raw.(type) {
+		case AnswerMap:
+			item = v
+		case map[string]interface{}:
+			for key, value := range v {
+				item[key] = value
+			}
+		case map[interface{}]interface{}:
+			for key, value := range v {
+				item[fmt.Sprintf("%v", key)] = value
+			}
+		default:
+			return nil, fmt.Errorf("expected each item to be a map, got `%v`", raw)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// writeGroup stores the items as the Group question's answer.
+func (p Prompt) writeGroup(e Question, items []AnswerMap) {
+	if Answers[p.Name] == nil {
+		Answers[p.Name] = make(AnswerMap)
+	}
+
+	Answers[p.Name][e.Name] = items
+	Answers[p.Name]["Raw"+e.Name] = items
+}
+
+// provideGroup answers the items of the Group question from the
+// provided list of items without asking anything. Without a provided
+// list, a count asks for that many items answered by their defaults.
+func (p Prompt) provideGroup(e Question, raw interface{}, provided bool) ([]AnswerMap, error) {
+	var rawItems []AnswerMap
+
+	if provided {
+		var err error
+		if rawItems, err = groupItems(raw); err != nil {
+			return nil, err
+		}
+	} else {
+		count, err := e.groupCount(p.scope())
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < count; i++ {
+			rawItems = append(rawItems, AnswerMap{})
+		}
+	}
+
+	if len(rawItems) < e.minItems() {
+		return nil, e.Validate.fail("minItems", "at least %d must be given", e.minItems())
+	}
+
+	if e.Validate.MaxItems > 0 && len(rawItems) > e.Validate.MaxItems {
+		return nil, e.Validate.fail("maxItems", "at most %d can be given", e.Validate.MaxItems)
+	}
+
+	items := []AnswerMap{}
+	for i, rawItem := range rawItems {
+		item := p.itemPrompt(e, i)
+		answers, err := item.Provide(rawItem)
+		delete(Answers, item.Name)
+
+		if err != nil {
+			return nil, fmt.Errorf("item %d, %s", i+1, err)
+		}
+
+		items = append(items, e.groupItem(answers))
+	}
+
+	return items, nil
+}
+
+// askGroup asks the Group question's questions once for each item,
+// unless the items have been provided.
+func (p Prompt) askGroup(e Question, provided AnswerMap) {
+	if raw, ok := provided[e.Name]; ok {
+		items, err := p.provideGroup(e, raw, true)
+		cmdutil.CheckCommandError(err, fmt.Sprintf("answering question, %s", e.Name))
+
+		p.writeGroup(e, items)
+
+		return
+	}
+
+	count, err := e.groupCount(p.scope())
+	cmdutil.CheckCommandError(err, fmt.Sprintf("asking question, %s", e.Name))
+
+	items := []AnswerMap{}
+	for i := 0; count < 0 || i < count; i++ {
+		if count < 0 {
+			if e.Validate.MaxItems > 0 && i >= e.Validate.MaxItems {
+				break
+			}
+
+			if i >= e.minItems() {
+				more := false
+				article := "a"
+				if i > 0 {
+					article = "another"
+				}
+
+				err := survey.AskOne(&survey.Confirm{
+					Message: fmt.Sprintf("Add %s %s?", article, e.groupLabel()),
+					Default: i == 0,
+				}, &more)
+				cmdutil.CheckCommandError(err, fmt.Sprintf("asking question, %s", e.Name))
+
+				if !more {
+					break
+				}
+			}
+		}
+
+		fmt.Printf("\n %s\n\n", aurora.Cyan(fmt.Sprintf("%s %d", e.groupLabel(), i+1)))
+
+		item := p.itemPrompt(e, i)
+		item.askQuestions(nil)
+
+		items = append(items, e.groupItem(Answers[item.Name]))
+		delete(Answers, item.Name)
+	}
+
+	p.writeGroup(e, items)
+}
@@ -19,11 +19,26 @@ type AnswerMap map[string]interface{}
 // Answers are the global stored answers
 var Answers = make(GlobalAnswers)
 
-// Prompt holds Prompt configuration.
+// Prompt holds Prompt configuration. The items of a Group question are
+// answered by a Prompt of their own, with the answers given before the
+// group as its parent answers.
 type Prompt struct {
 	Name      string `yaml:"name"`
 	Answers   AnswerMap
 	Questions []Question `yaml:"questions"`
+	Computed  []Computed `yaml:"computed"`
+	parent    AnswerMap
+}
+
+// scope returns the answers the Prompt's questions are asked against,
+// for their `when`, defaults, validation and transforms: the parent
+// answers followed by the Prompt's own answers.
+func (p Prompt) scope() AnswerMap {
+	if p.parent == nil {
+		return Answers[p.Name]
+	}
+
+	return p.parent.Merge(Answers[p.Name])
 }
 
 // Answer holds the Prompt Answer configuration.
@@ -40,7 +55,21 @@ func (answer *Answer) WriteAnswer(name string, value interface{}) error {
 		Answers[answer.prompt.Name] = make(AnswerMap)
 	}
 
-	Answers[answer.prompt.Name][answer.name] = answer.question.Transformer()(value)
+	if IsTypedPromptType(answer.question.Type) {
+		typed, err := answer.question.Coerce(value)
+		if err != nil {
+			return err
+		}
+
+		value = typed
+	}
+
+	transformed, err := answer.question.ApplyTransforms(value, answer.prompt.scope())
+	if err != nil {
+		return err
+	}
+
+	Answers[answer.prompt.Name][answer.name] = transformed
 	Answers[answer.prompt.Name]["Raw"+answer.name] = value
 
 	return nil
@@ -62,28 +91,78 @@ type WhenEnvironment struct {
 
 // Ask initializes the survey prompt, asking the questions provided.
 func (p Prompt) Ask() AnswerMap {
+	return p.AskWith(nil)
+}
+
+// AskWith initializes the survey prompt, asking the questions provided
+// except for those already answered within the provided answers.
+func (p Prompt) AskWith(provided AnswerMap) AnswerMap {
+	err := p.CheckTransforms()
+	cmdutil.CheckCommandError(err, "checking questions")
+
 	fmt.Println()
 	fmt.Printf(" %s \n\n", aurora.Green(fmt.Sprintf("%s questions:", p.Name)))
+
+	p.askQuestions(provided)
+
+	err = p.Compute()
+	cmdutil.CheckCommandError(err, "computing answers")
+
+	fmt.Println()
+
+	return Answers[p.Name]
+}
+
+// askQuestions asks each question whose `when` is true, in order.
+func (p Prompt) askQuestions(provided AnswerMap) {
 	for _, e := range p.Questions {
 		if when.ImplicitlyTrue(e.When) {
-			err := survey.AskOne(e.Prompt(), &Answer{prompt: p, name: e.Name, question: e}, survey.WithValidator(e.CheckValid))
-			cmdutil.CheckCommandError(err, fmt.Sprintf("asking question, %s", e.Name))
+			p.ask(e, provided)
 
 			continue
 		}
 
 		env := WhenEnvironment{
-			Answers: Answers[p.Name],
+			Answers: p.scope(),
 			Env:     util.GetEnvMap(),
 		}
 
 		if when.True(e.When, env) {
-			err := survey.AskOne(e.Prompt(), &Answer{prompt: p, name: e.Name, question: e}, survey.WithValidator(e.CheckValid))
-			cmdutil.CheckCommandError(err, fmt.Sprintf("asking question, %s", e.Name))
+			p.ask(e, provided)
 		}
 	}
+}
 
-	fmt.Println()
+// ask asks a single question, unless an answer has been provided for it.
+func (p Prompt) ask(e Question, provided AnswerMap) {
+	if e.Type == "Group" {
+		p.askGroup(e, provided)
 
-	return Answers[p.Name]
+		return
+	}
+
+	answer := &Answer{prompt: p, name: e.Name, question: e}
+
+	if raw, ok := provided[e.Name]; ok {
+		value, err := e.Coerce(raw)
+		if err == nil {
+			err = e.CheckValidWith(value, p.scope())
+		}
+		if err == nil {
+			err = answer.WriteAnswer(e.Name, value)
+		}
+		cmdutil.CheckCommandError(err, fmt.Sprintf("answering question, %s", e.Name))
+
+		return
+	}
+
+	e, err := e.WithDefault(p.scope())
+	cmdutil.CheckCommandError(err, fmt.Sprintf("asking question, %s", e.Name))
+
+	validator := func(ans interface{}) error {
+		return e.CheckValidWith(ans, p.scope())
+	}
+
+	err = survey.AskOne(e.Prompt(), answer, survey.WithValidator(validator))
+	cmdutil.CheckCommandError(err, fmt.Sprintf("asking question, %s", e.Name))
 }
@@ -0,0 +1,143 @@
+package prompt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/mitchellh/go-homedir"
+)
+
+// PathInput is a survey.Input that completes file and directory names
+// when tab is pressed.
+type PathInput struct {
+	survey.Input
+	DirsOnly bool
+}
+
+// completePath returns the longest completion of the partially typed
+// path shared by every matching entry. A single matching directory is
+// completed with a trailing separator.
+func completePath(partial string, dirsOnly bool) string {
+	expanded, _ := homedir.Expand(partial)
+
+	dir, prefix := filepath.Split(expanded)
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return partial
+	}
+
+	var matches []os.FileInfo
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), prefix) && (!dirsOnly || entry.IsDir()) {
+			matches = append(matches, entry)
+		}
+	}
+
+	if len(matches) == 0 {
+		return partial
+	}
+
+	common := matches[0].Name()
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(match.Name(), common) {
+			common = common[:len(common)-1]
+		}
+	}
+
+	completed := partial + strings.TrimPrefix(common, prefix)
+	if len(matches) == 1 && matches[0].IsDir() {
+		completed += string(filepath.Separator)
+	}
+
+	return completed
+}
+
+// Prompt asks for the path, reading it a rune at a time so tab can
+// complete it.
+func (i *PathInput) Prompt(config *survey.PromptConfig) (interface{}, error) {
+	err := i.Render(
+		survey.InputQuestionTemplate,
+		survey.InputTemplateData{
+			Input:  i.Input,
+			Config: config,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	rr := i.NewRuneReader()
+	rr.SetTermMode()
+	defer rr.RestoreTermMode()
+
+	cursor := i.NewCursor()
+	out := i.Stdio().Out
+
+	line := []rune{}
+	for {
+		r, _, err := rr.ReadRune()
+		if err != nil {
+			return string(line), err
+		}
+
+		switch r {
+		case terminal.KeyInterrupt:
+			fmt.Fprint(out, "\r\n")
+
+			return string(line), terminal.InterruptErr
+		case terminal.KeyEnter, '\n', terminal.KeyEndTransmission:
+			if string(line) == config.HelpInput && i.Help != "" {
+				cursor.Back(len(line))
+				terminal.EraseLine(out, terminal.ERASE_LINE_END)
+				line = []rune{}
+
+				err = i.Render(
+					survey.InputQuestionTemplate,
+					survey.InputTemplateData{
+						Input:    i.Input,
+						ShowHelp: true,
+						Config:   config,
+					},
+				)
+				if err != nil {
+					return "", err
+				}
+
+				continue
+			}
+
+			fmt.Fprint(out, "\r\n")
+			cursor.PreviousLine(1)
+
+			if len(line) == 0 {
+				return i.Default, nil
+			}
+
+			return string(line), nil
+		case '\t':
+			completed := []rune(completePath(string(line), i.DirsOnly))
+			fmt.Fprint(out, string(completed[len(line):]))
+			line = completed
+		case terminal.KeyBackspace, terminal.KeyDelete:
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+				cursor.Back(1)
+				terminal.EraseLine(out, terminal.ERASE_LINE_END)
+			}
+		default:
+			if r >= ' ' {
+				line = append(line, r)
+				fmt.Fprintf(out, "%c", r)
+			}
+		}
+	}
+}
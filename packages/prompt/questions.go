@@ -1,21 +1,28 @@
 package prompt
 
 import (
-	"errors"
 	"fmt"
 	"strconv"
 
 	"github.com/AlecAivazis/survey/v2"
-	"github.com/gosimple/slug"
-	"github.com/lavrahq/cli/util/cmdutil"
 )
 
 // QuestionValidation provides validation options for Survey Question
-// instances.
+// instances. Messages replaces the error shown when a validator fails,
+// keyed by the validator's name.
 type QuestionValidation struct {
-	Required  bool `yaml:"required"`
-	MinLength int  `yaml:"minLength"`
-	MaxLength int  `yaml:"maxLength"`
+	Required  bool              `yaml:"required"`
+	MinLength int               `yaml:"minLength"`
+	MaxLength int               `yaml:"maxLength"`
+	Min       *float64          `yaml:"min"`
+	Max       *float64          `yaml:"max"`
+	MinItems  int               `yaml:"minItems"`
+	MaxItems  int               `yaml:"maxItems"`
+	Pattern   string            `yaml:"pattern"`
+	Enum      []string          `yaml:"enum"`
+	Unique    []string          `yaml:"unique"`
+	Custom    string            `yaml:"custom"`
+	Messages  map[string]string `yaml:"messages"`
 }
 
 // QuestionOptions provides option storage for Survey Question
@@ -31,16 +38,25 @@ type QuestionOptions struct {
 	HideDefault   bool     `yaml:"hideDefault"`
 	AppendDefault bool     `yaml:"appendDefault"`
 	FileName      string   `yaml:"fileName"`
+	Float         bool     `yaml:"float"`
+	PathType      string   `yaml:"pathType"`
+	MustExist     bool     `yaml:"mustExist"`
+	Layout        string   `yaml:"layout"`
 }
 
-// Question holds the Survey question configs.
+// Question holds the Survey question configs. A Group question asks
+// its nested Questions once for each item, repeating as set by Repeat
+// and Count.
 type Question struct {
 	Name      string             `yaml:"name"`
 	Type      string             `yaml:"type"`
 	Options   QuestionOptions    `yaml:"prompt"`
 	Validate  QuestionValidation `yaml:"validate"`
-	Transform string             `yaml:"transform"`
+	Transform Transforms         `yaml:"transform"`
 	When      string             `yaml:"when"`
+	Questions []Question         `yaml:"questions"`
+	Repeat    string             `yaml:"repeat"`
+	Count     string             `yaml:"count"`
 }
 
 // IsValidPromptType checks that the given promptType is valid.
@@ -53,21 +69,31 @@ func IsValidPromptType(promptType string) bool {
 		"Confirm",
 		"Select",
 		"MultiSelect",
-		"Editor":
+		"Editor",
+		"Group":
 		return true
 	}
 
-	return false
+	return IsTypedPromptType(promptType)
 }
 
-// IsValidTransformerType checks that the given promptType is valid.
+// IsValidTransformerType checks that the given transformerType is
+// valid, including its argument.
 func IsValidTransformerType(transformerType string) bool {
-	switch transformerType {
+	name, arg := ParseTransform(transformerType)
+	if _, ok := stringTransforms[name]; ok {
+		return arg == ""
+	}
+
+	switch name {
 	case
-		"Title",
-		"ToLower",
-		"Slug":
+		"ParseInt",
+		"ParseBool":
+		return arg == ""
+	case "Split":
 		return true
+	case "expr":
+		return arg != ""
 	}
 
 	return false
@@ -77,72 +103,52 @@ func IsValidTransformerType(transformerType string) bool {
 func IsValidValidatorType(validatorType string) bool {
 	switch validatorType {
 	case
-		"Required",
-		"MinLength",
-		"MaxLength":
+		"required",
+		"minLength",
+		"maxLength",
+		"min",
+		"max",
+		"minItems",
+		"maxItems",
+		"pattern",
+		"enum",
+		"unique",
+		"custom":
 		return true
 	}
 
 	return false
 }
 
-// AsSurveyQuestion coerces the question into a survey.Question type.
-func (question Question) AsSurveyQuestion() survey.Question {
-	return survey.Question{
-		Name:      question.Name,
-		Prompt:    question.Prompt(),
-		Validate:  question.CheckValid,
-		Transform: question.Transformer(),
-	}
-}
-
-func toSlug(ans interface{}) interface{} {
-	return slug.Make(ans.(string))
-}
+// checkTransforms checks that every transform of the questions, and of
+// the questions nested within Group questions, is valid.
+func checkTransforms(questions []Question) error {
+	for _, question := range questions {
+		for _, transform := range question.Transform {
+			if !IsValidTransformerType(transform) {
+				return fmt.Errorf("the `%s` transform of the `%s` question is not valid", transform, question.Name)
+			}
+		}
 
-// Transformer returns the survey.Transformer for the specific Question.
-func (question Question) Transformer() survey.Transformer {
-	if !IsValidPromptType(question.Type) {
-		cmdutil.ExitWithMessage(fmt.Sprintf("An invaid Transformer was specified for the `%s` Question.\n", question.Name))
+		if err := checkTransforms(question.Questions); err != nil {
+			return err
+		}
 	}
 
-	switch question.Transform {
-	case "Title":
-		return survey.Title
-	case "ToLower":
-		return survey.ToLower
-	case "Slug":
-		return toSlug
-	}
+	return nil
+}
 
-	return func(ans interface{}) interface{} {
-		return ans
-	}
+// CheckTransforms checks that every transform of the Prompt's questions
+// is valid before any are asked, as an invalid transform would reject
+// every answer given.
+func (p Prompt) CheckTransforms() error {
+	return checkTransforms(p.Questions)
 }
 
 // CheckValid checks if the question's answer is valid according to the
 // specifications for the specified Question.
 func (question Question) CheckValid(ans interface{}) error {
-	// since we are validating an Input, the assertion will always succeed
-	if question.Validate.Required {
-		if str, ok := ans.(string); !ok || len(str) == 0 {
-			return errors.New("this response is required")
-		}
-	}
-
-	if question.Validate.MinLength > 1 {
-		if str, ok := ans.(string); !ok || len(str) < question.Validate.MinLength {
-			return fmt.Errorf("this response must be %d or more characters", question.Validate.MinLength)
-		}
-	}
-
-	if question.Validate.MaxLength > 0 {
-		if str, ok := ans.(string); !ok || len(str) > question.Validate.MaxLength {
-			return fmt.Errorf("this response must have %d or less characters", question.Validate.MaxLength)
-		}
-	}
-
-	return nil
+	return question.CheckValidWith(ans, nil)
 }
 
 // Prompt constructs a new survey.Prompt instance from the Question.
@@ -172,7 +178,7 @@ func (question Question) Prompt() survey.Prompt {
 	case "Confirm":
 		var theDefault = true
 
-		if question.Options.Default == "" {
+		if question.Options.Default != "" {
 			theDefault, _ = strconv.ParseBool(question.Options.Default)
 		}
 
@@ -209,6 +215,21 @@ func (question Question) Prompt() survey.Prompt {
 			AppendDefault: question.Options.AppendDefault,
 			// FileName:      question.Options.FileName,
 		}
+	case "Path":
+		return &PathInput{
+			Input: survey.Input{
+				Message: question.Options.Message,
+				Help:    question.Options.Help,
+				Default: question.Options.Default,
+			},
+			DirsOnly: question.Options.PathType == PathTypeDir,
+		}
+	case "Number", "Date", "URL", "Email", "Port":
+		return &survey.Input{
+			Message: question.Options.Message,
+			Help:    question.Options.Help,
+			Default: question.Options.Default,
+		}
 	}
 
 	return nil
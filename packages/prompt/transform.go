@@ -0,0 +1,222 @@
+package prompt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2/core"
+	"github.com/gosimple/slug"
+	"github.com/lavrahq/cli/packages/funcs"
+	"github.com/lavrahq/cli/packages/when"
+	"github.com/lavrahq/cli/util"
+)
+
+// ExprTransformPrefix prefixes transforms evaluated as expressions.
+const ExprTransformPrefix = "expr:"
+
+// stringTransforms are the transforms applied to the text of an answer.
+var stringTransforms = map[string]func(string) string{
+	"Title":     strings.Title,
+	"ToLower":   strings.ToLower,
+	"ToUpper":   strings.ToUpper,
+	"Trim":      strings.TrimSpace,
+	"Slug":      slug.Make,
+	"CamelCase": funcs.Camel,
+	"SnakeCase": funcs.Snake,
+	"KebabCase": funcs.Kebab,
+}
+
+// Transforms lists the transforms applied to an answer, in order. A
+// single transform can be given on its own rather than as a list.
+type Transforms []string
+
+// UnmarshalYAML reads either a single transform or a list.
+func (transforms *Transforms) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*transforms = nil
+		if single != "" {
+			*transforms = Transforms{single}
+		}
+
+		return nil
+	}
+
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+
+	*transforms = list
+
+	return nil
+}
+
+// TransformEnvironment is the object passed into `expr:` transforms.
+type TransformEnvironment struct {
+	Answer  interface{}
+	Answers AnswerMap
+	Env     map[string]string
+}
+
+// ParseTransform splits the transform into its name and argument, so
+// `Split(",")` becomes `Split` and `,`, and `expr:Answer * 2` becomes
+// `expr` and `Answer * 2`.
+func ParseTransform(transform string) (string, string) {
+	if strings.HasPrefix(transform, ExprTransformPrefix) {
+		return "expr", strings.TrimSpace(strings.TrimPrefix(transform, ExprTransformPrefix))
+	}
+
+	open := strings.Index(transform, "(")
+	if open == -1 || !strings.HasSuffix(transform, ")") {
+		return transform, ""
+	}
+
+	arg := strings.TrimSpace(transform[open+1 : len(transform)-1])
+	if unquoted, err := strconv.Unquote(arg); err == nil {
+		arg = unquoted
+	} else if len(arg) >= 2 && strings.HasPrefix(arg, "'") && strings.HasSuffix(arg, "'") {
+		arg = arg[1 : len(arg)-1]
+	}
+
+	return transform[:open], arg
+}
+
+// mapText applies the function to the text of the answer, or to each
+// item of a list answer. Answers that are not text pass through.
+func mapText(value interface{}, f func(string) (interface{}, error)) (interface{}, error) {
+	var items []string
+
+	switch v := value.(type) {
+	case string:
+		return f(v)
+	case core.OptionAnswer:
+		return f(v.Value)
+	case []string:
+		items = v
+	case []core.OptionAnswer:
+		for _, option := range v {
+			items = append(items, option.Value)
+		}
+	case []interface{}:
+		mapped := make([]interface{}, len(v))
+		for i, item := range v {
+			var err error
+			if mapped[i], err = mapText(item, f); err != nil {
+				return nil, err
+			}
+		}
+
+		return mapped, nil
+	default:
+		return value, nil
+	}
+
+	mapped := make([]interface{}, len(items))
+	allText := true
+	for i, item := range items {
+		var err error
+		if mapped[i], err = f(item); err != nil {
+			return nil, err
+		}
+
+		_, isText := mapped[i].(string)
+		allText = allText && isText
+	}
+
+	if !allText {
+		return mapped, nil
+	}
+
+	texts := make([]string, len(mapped))
+	for i, item := range mapped {
+		texts[i] = item.(string)
+	}
+
+	return texts, nil
+}
+
+// split splits the text on the separator, trimming each item and
+// leaving out empty items.
+func split(text string, sep string) []string {
+	items := []string{}
+
+	for _, item := range strings.Split(text, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// applyTransform applies a single transform to the value.
+func applyTransform(transform string, value interface{}, answers AnswerMap) (interface{}, error) {
+	name, arg := ParseTransform(transform)
+
+	if f, ok := stringTransforms[name]; ok {
+		return mapText(value, func(text string) (interface{}, error) {
+			return f(text), nil
+		})
+	}
+
+	switch name {
+	case "Split":
+		if arg == "" {
+			arg = ","
+		}
+
+		text, ok := value.(string)
+		if !ok {
+			return value, nil
+		}
+
+		return split(text, arg), nil
+	case "ParseInt":
+		return mapText(value, func(text string) (interface{}, error) {
+			number, err := strconv.Atoi(strings.TrimSpace(text))
+			if err != nil {
+				return nil, fmt.Errorf("`%s` is not a whole number", text)
+			}
+
+			return number, nil
+		})
+	case "ParseBool":
+		return mapText(value, func(text string) (interface{}, error) {
+			b, err := strconv.ParseBool(strings.TrimSpace(text))
+			if err != nil {
+				return nil, fmt.Errorf("`%s` is not true or false", text)
+			}
+
+			return b, nil
+		})
+	case "expr":
+		result, err := when.Eval(arg, TransformEnvironment{
+			Answer:  value,
+			Answers: answers,
+			Env:     util.GetEnvMap(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("the transform `%s` does not evaluate, %s", arg, strings.TrimSpace(err.Error()))
+		}
+
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("`%s` is not a valid transform", transform)
+}
+
+// ApplyTransforms applies the question's transforms to the answer in
+// order, evaluating `expr:` transforms against the answers given
+// before it.
+func (question Question) ApplyTransforms(value interface{}, answers AnswerMap) (interface{}, error) {
+	for _, transform := range question.Transform {
+		var err error
+		if value, err = applyTransform(transform, value, answers); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
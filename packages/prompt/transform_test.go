@@ -0,0 +1,90 @@
+package prompt
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2/core"
+)
+
+func TestApplyTransforms(t *testing.T) {
+	tests := []struct {
+		name       string
+		transforms Transforms
+		answer     interface{}
+		answers    AnswerMap
+		want       interface{}
+	}{
+		{"no transforms", nil, "My App", nil, "My App"},
+		{"ToLower", Transforms{"ToLower"}, "My App", nil, "my app"},
+		{"in order", Transforms{"Trim", "SnakeCase"}, "  My App ", nil, "my_app"},
+		{"Slug", Transforms{"Slug"}, "My App!", nil, "my-app"},
+		{"KebabCase", Transforms{"KebabCase"}, "MyApp", nil, "my-app"},
+		{"select answer", Transforms{"ToUpper"}, core.OptionAnswer{Value: "go"}, nil, "GO"},
+		{"multiselect items", Transforms{"ToUpper"}, []core.OptionAnswer{{Value: "a"}, {Value: "b"}}, nil, []string{"A", "B"}},
+		{"Split on commas", Transforms{"Split"}, "a, b,,c", nil, []string{"a", "b", "c"}},
+		{"Split on a separator", Transforms{`Split(";")`}, "a;b", nil, []string{"a", "b"}},
+		{"Split then ParseInt", Transforms{"Split", "ParseInt"}, "1,2", nil, []interface{}{1, 2}},
+		{"ParseInt", Transforms{"ParseInt"}, " 42 ", nil, 42},
+		{"ParseBool", Transforms{"ParseBool"}, "true", nil, true},
+		{"non-text answers pass through", Transforms{"ToUpper"}, 3, nil, 3},
+		{"expr", Transforms{"expr: Answer * 2"}, 21, nil, 42},
+		{"expr with earlier answers", Transforms{`expr: Answers.Name + "_" + Answer`}, "db", AnswerMap{"Name": "app"}, "app_db"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			question := Question{Name: "Q", Transform: test.transforms}
+
+			got, err := question.ApplyTransforms(test.answer, test.answers)
+			if err != nil {
+				t.Fatalf("ApplyTransforms(%v) returned %s", test.answer, err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ApplyTransforms(%v) = %#v, want %#v", test.answer, got, test.want)
+			}
+		})
+	}
+}
+
+func TestApplyTransformsErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		transforms Transforms
+		answer     interface{}
+	}{
+		{"unknown transform", Transforms{"Bogus"}, "a"},
+		{"ParseInt on text", Transforms{"ParseInt"}, "abc"},
+		{"ParseBool on text", Transforms{"ParseBool"}, "maybe"},
+		{"expr that does not evaluate", Transforms{"expr: Answer +"}, "a"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			question := Question{Name: "Q", Transform: test.transforms}
+
+			if _, err := question.ApplyTransforms(test.answer, nil); err == nil {
+				t.Errorf("ApplyTransforms(%v) returned no error", test.answer)
+			}
+		})
+	}
+}
+
+func TestCheckTransforms(t *testing.T) {
+	valid := Prompt{Questions: []Question{
+		{Name: "Name", Transform: Transforms{"Trim", `Split(",")`, "expr: Answer"}},
+	}}
+	if err := valid.CheckTransforms(); err != nil {
+		t.Errorf("CheckTransforms() returned %s", err)
+	}
+
+	nested := Prompt{Questions: []Question{
+		{Name: "Services", Type: "Group", Questions: []Question{
+			{Name: "Port", Transform: Transforms{"ParseInt(10)"}},
+		}},
+	}}
+	if err := nested.CheckTransforms(); err == nil {
+		t.Error("CheckTransforms() returned no error for an invalid nested transform")
+	}
+}
@@ -0,0 +1,202 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// Path types a Path question can be limited to.
+const (
+	PathTypeFile = "file"
+	PathTypeDir  = "dir"
+)
+
+// DefaultDateLayout is the layout Date answers are given in, unless the
+// question sets its own.
+const DefaultDateLayout = "2006-01-02"
+
+// IsTypedPromptType returns true if answers to the prompt type are
+// converted from the text typed into a typed value, such as an int or
+// a time.Time.
+func IsTypedPromptType(promptType string) bool {
+	switch promptType {
+	case
+		"Number",
+		"Path",
+		"Date",
+		"URL",
+		"Email",
+		"Port":
+		return true
+	}
+
+	return false
+}
+
+// IsValidPathType checks that the given pathType is valid.
+func IsValidPathType(pathType string) bool {
+	return pathType == "" || pathType == PathTypeFile || pathType == PathTypeDir
+}
+
+// dateLayout returns the layout Date answers are given in.
+func (question Question) dateLayout() string {
+	if question.Options.Layout != "" {
+		return question.Options.Layout
+	}
+
+	return DefaultDateLayout
+}
+
+// coerceNumber converts the value into an int, or a float64 when the
+// question allows decimals.
+func (question Question) coerceNumber(value interface{}) (interface{}, error) {
+	var number float64
+
+	switch v := value.(type) {
+	case int:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	case float64:
+		number = v
+	default:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(fmt.Sprintf("%v", v)), 64)
+		if err != nil {
+			return nil, errors.New("this response must be a number")
+		}
+
+		number = parsed
+	}
+
+	if question.Options.Float {
+		return number, nil
+	}
+
+	if number != math.Trunc(number) {
+		return nil, errors.New("this response must be a whole number")
+	}
+
+	return int(number), nil
+}
+
+// coerceTyped converts the answer to a typed question into its typed
+// value. An empty answer is converted into nil.
+func (question Question) coerceTyped(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	if str, ok := value.(string); ok && strings.TrimSpace(str) == "" {
+		return nil, nil
+	}
+
+	str := strings.TrimSpace(fmt.Sprintf("%v", value))
+
+	switch question.Type {
+	case "Number":
+		return question.coerceNumber(value)
+	case "Port":
+		port, err := Question{Type: "Number"}.coerceNumber(value)
+		if err != nil || port.(int) < 1 || port.(int) > 65535 {
+			return nil, errors.New("this response must be a port between 1 and 65535")
+		}
+
+		return port, nil
+	case "Path":
+		expanded, err := homedir.Expand(str)
+		if err != nil {
+			return nil, err
+		}
+
+		return filepath.Clean(expanded), nil
+	case "Date":
+		if date, ok := value.(time.Time); ok {
+			return date, nil
+		}
+
+		date, err := time.Parse(question.dateLayout(), str)
+		if err != nil {
+			return nil, fmt.Errorf("this response must be a date formatted as %s", question.dateLayout())
+		}
+
+		return date, nil
+	case "URL":
+		if u, ok := value.(*url.URL); ok {
+			return u, nil
+		}
+
+		u, err := url.Parse(str)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, errors.New("this response must be an absolute URL, such as https://example.com")
+		}
+
+		return u, nil
+	case "Email":
+		address, err := mail.ParseAddress(str)
+		if err != nil {
+			return nil, errors.New("this response must be an email address")
+		}
+
+		return address.Address, nil
+	}
+
+	return value, nil
+}
+
+// checkTyped checks the typed answer against the question's built-in
+// validation.
+func (question Question) checkTyped(value interface{}) error {
+	if path, ok := value.(string); ok && question.Type == "Path" {
+		return question.checkPath(path)
+	}
+
+	return nil
+}
+
+// checkPath checks the path exists, when the question requires it, and
+// is of the question's path type.
+func (question Question) checkPath(file string) error {
+	info, err := os.Stat(file)
+	if os.IsNotExist(err) {
+		if question.Options.MustExist {
+			return fmt.Errorf("%s does not exist", file)
+		}
+
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if question.Options.PathType == PathTypeDir && !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", file)
+	}
+
+	if question.Options.PathType == PathTypeFile && info.IsDir() {
+		return fmt.Errorf("%s is not a file", file)
+	}
+
+	return nil
+}
+
+// plainTyped converts a typed answer back into the text it was given as.
+func (question Question) plainTyped(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(question.dateLayout())
+	case *url.URL:
+		return v.String()
+	}
+
+	return value
+}
@@ -0,0 +1,224 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lavrahq/cli/packages/when"
+	"github.com/lavrahq/cli/util"
+)
+
+// ValidationEnvironment is the object passed into the `custom`
+// validation expression.
+type ValidationEnvironment struct {
+	Answer  interface{}
+	Answers AnswerMap
+	Env     map[string]string
+}
+
+// fail returns the validator's custom message when one is set, or the
+// formatted message otherwise.
+func (validation QuestionValidation) fail(validator string, format string, args ...interface{}) error {
+	if message, ok := validation.Messages[validator]; ok && message != "" {
+		return errors.New(message)
+	}
+
+	return fmt.Errorf(format, args...)
+}
+
+// isEmptyAnswer returns true if nothing was answered.
+func isEmptyAnswer(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	str, ok := value.(string)
+
+	return ok && len(str) == 0
+}
+
+// answerNumber returns the answer as a float64 if it is a number.
+func answerNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+
+	return 0, false
+}
+
+// contains returns true if the value is one of the values.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkText checks the text of a single answer, or of a single
+// MultiSelect item, against the pattern, enum and unique validators.
+func (validation QuestionValidation) checkText(text string) error {
+	if validation.Pattern != "" {
+		pattern, err := regexp.Compile(validation.Pattern)
+		if err != nil {
+			return fmt.Errorf("the validation pattern is invalid, %s", err)
+		}
+
+		if !pattern.MatchString(text) {
+			return validation.fail("pattern", "`%s` must match %s", text, validation.Pattern)
+		}
+	}
+
+	if len(validation.Enum) > 0 && !contains(validation.Enum, text) {
+		return validation.fail("enum", "`%s` is not one of: %s", text, strings.Join(validation.Enum, ", "))
+	}
+
+	if contains(validation.Unique, text) {
+		return validation.fail("unique", "`%s` is already taken", text)
+	}
+
+	return nil
+}
+
+// checkValue checks a single answer.
+func (validation QuestionValidation) checkValue(value interface{}, text string) error {
+	if number, ok := answerNumber(value); ok {
+		if validation.Min != nil && number < *validation.Min {
+			return validation.fail("min", "this response must be %v or more", *validation.Min)
+		}
+
+		if validation.Max != nil && number > *validation.Max {
+			return validation.fail("max", "this response must be %v or less", *validation.Max)
+		}
+	}
+
+	if validation.MinLength > 0 && len(text) < validation.MinLength {
+		return validation.fail("minLength", "this response must be %d or more characters", validation.MinLength)
+	}
+
+	if validation.MaxLength > 0 && len(text) > validation.MaxLength {
+		return validation.fail("maxLength", "this response must be %d or less characters", validation.MaxLength)
+	}
+
+	return validation.checkText(text)
+}
+
+// checkItems checks the items selected within a MultiSelect answer.
+func (validation QuestionValidation) checkItems(items []string) error {
+	if validation.Required && len(items) == 0 {
+		return validation.fail("required", "this response is required")
+	}
+
+	if validation.MinItems > 0 && len(items) < validation.MinItems {
+		return validation.fail("minItems", "at least %d must be selected", validation.MinItems)
+	}
+
+	if validation.MaxItems > 0 && len(items) > validation.MaxItems {
+		return validation.fail("maxItems", "at most %d can be selected", validation.MaxItems)
+	}
+
+	for _, item := range items {
+		if err := validation.checkText(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkCustom evaluates the `custom` expression against the answer and
+// the answers given before it.
+func (validation QuestionValidation) checkCustom(answer interface{}, answers AnswerMap) error {
+	if validation.Custom == "" {
+		return nil
+	}
+
+	valid, err := when.Check(validation.Custom, ValidationEnvironment{
+		Answer:  answer,
+		Answers: answers,
+		Env:     util.GetEnvMap(),
+	})
+	if err != nil {
+		return fmt.Errorf("the custom validation does not evaluate, %s", strings.TrimSpace(err.Error()))
+	}
+
+	if !valid {
+		return validation.fail("custom", "this response is invalid")
+	}
+
+	return nil
+}
+
+// CheckValidWith checks if the question's answer is valid according to
+// the specifications for the specified Question, evaluating `custom`
+// validation against the answers given before it. Answers left empty
+// are only checked when the question is required or has a minLength.
+// The answer must also make it through the question's transforms.
+func (question Question) CheckValidWith(ans interface{}, answers AnswerMap) error {
+	value := ans
+	if IsTypedPromptType(question.Type) {
+		typed, err := question.coerceTyped(ans)
+		if err != nil {
+			return err
+		}
+
+		if err := question.checkTyped(typed); err != nil {
+			return err
+		}
+
+		value = typed
+	}
+
+	if err := question.checkValidations(value, answers); err != nil {
+		return err
+	}
+
+	_, err := question.ApplyTransforms(value, answers)
+
+	return err
+}
+
+// checkValidations checks the answer against the question's `validate`
+// entries.
+func (question Question) checkValidations(value interface{}, answers AnswerMap) error {
+	validation := question.Validate
+	plain := question.Plain(value)
+
+	if items, ok := plain.([]string); ok {
+		if err := validation.checkItems(items); err != nil {
+			return err
+		}
+
+		return validation.checkCustom(items, answers)
+	}
+
+	text := ""
+	if isEmptyAnswer(value) {
+		if validation.Required {
+			return validation.fail("required", "this response is required")
+		}
+
+		if validation.MinLength == 0 {
+			return nil
+		}
+	} else {
+		text = fmt.Sprintf("%v", plain)
+	}
+
+	if err := validation.checkValue(value, text); err != nil {
+		return err
+	}
+
+	if question.Type == "Select" {
+		value = plain
+	}
+
+	return validation.checkCustom(value, answers)
+}
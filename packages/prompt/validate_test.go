@@ -0,0 +1,137 @@
+package prompt
+
+import (
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2/core"
+)
+
+func TestCheckValidWith(t *testing.T) {
+	two := 2.0
+	ten := 10.0
+
+	tests := []struct {
+		name     string
+		question Question
+		answer   interface{}
+		answers  AnswerMap
+		valid    bool
+	}{
+		{
+			name:     "optional empty answer",
+			question: Question{Type: "Input", Validate: QuestionValidation{Pattern: "^a"}},
+			answer:   "",
+			valid:    true,
+		},
+		{
+			name:     "required empty answer",
+			question: Question{Type: "Input", Validate: QuestionValidation{Required: true}},
+			answer:   "",
+		},
+		{
+			name:     "minLength",
+			question: Question{Type: "Input", Validate: QuestionValidation{MinLength: 3}},
+			answer:   "ab",
+		},
+		{
+			name:     "maxLength",
+			question: Question{Type: "Input", Validate: QuestionValidation{MaxLength: 3}},
+			answer:   "abc",
+			valid:    true,
+		},
+		{
+			name:     "pattern",
+			question: Question{Type: "Input", Validate: QuestionValidation{Pattern: "^[a-z]+$"}},
+			answer:   "Abc",
+		},
+		{
+			name:     "enum",
+			question: Question{Type: "Input", Validate: QuestionValidation{Enum: []string{"a", "b"}}},
+			answer:   "b",
+			valid:    true,
+		},
+		{
+			name:     "unique",
+			question: Question{Type: "Input", Validate: QuestionValidation{Unique: []string{"taken"}}},
+			answer:   "taken",
+		},
+		{
+			name:     "number within range",
+			question: Question{Type: "Number", Validate: QuestionValidation{Min: &two, Max: &ten}},
+			answer:   "5",
+			valid:    true,
+		},
+		{
+			name:     "number below min",
+			question: Question{Type: "Number", Validate: QuestionValidation{Min: &two}},
+			answer:   "1",
+		},
+		{
+			name:     "number that is not a number",
+			question: Question{Type: "Number"},
+			answer:   "five",
+		},
+		{
+			name:     "multiselect minItems",
+			question: Question{Type: "MultiSelect", Validate: QuestionValidation{MinItems: 2}},
+			answer:   []core.OptionAnswer{{Value: "a"}},
+		},
+		{
+			name:     "multiselect items checked against enum",
+			question: Question{Type: "MultiSelect", Validate: QuestionValidation{Enum: []string{"a", "b"}}},
+			answer:   []core.OptionAnswer{{Value: "a"}, {Value: "b"}},
+			valid:    true,
+		},
+		{
+			name:     "select custom against the option value",
+			question: Question{Type: "Select", Validate: QuestionValidation{Custom: `Answer == "b"`}},
+			answer:   core.OptionAnswer{Value: "b", Index: 1},
+			valid:    true,
+		},
+		{
+			name:     "custom against earlier answers",
+			question: Question{Type: "Input", Validate: QuestionValidation{Custom: "Answer != Answers.Name"}},
+			answer:   "api",
+			answers:  AnswerMap{"Name": "api"},
+		},
+		{
+			name:     "custom that does not evaluate",
+			question: Question{Type: "Input", Validate: QuestionValidation{Custom: "Answer +"}},
+			answer:   "api",
+		},
+		{
+			name:     "answer the transforms fail on",
+			question: Question{Type: "Input", Transform: Transforms{"ParseInt"}},
+			answer:   "abc",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.question.CheckValidWith(test.answer, test.answers)
+
+			if test.valid && err != nil {
+				t.Errorf("CheckValidWith(%v) returned %s", test.answer, err)
+			}
+
+			if !test.valid && err == nil {
+				t.Errorf("CheckValidWith(%v) returned no error", test.answer)
+			}
+		})
+	}
+}
+
+func TestCheckValidWithMessages(t *testing.T) {
+	question := Question{
+		Type: "Input",
+		Validate: QuestionValidation{
+			Required: true,
+			Messages: map[string]string{"required": "a name is needed"},
+		},
+	}
+
+	err := question.CheckValidWith("", nil)
+	if err == nil || err.Error() != "a name is needed" {
+		t.Errorf("CheckValidWith() returned %v, want the custom message", err)
+	}
+}
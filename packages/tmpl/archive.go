@@ -0,0 +1,503 @@
+package tmpl
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lavrahq/cli/util"
+	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/ulikunitz/xz"
+	"gopkg.in/src-d/go-git.v4"
+)
+
+// Archive formats templates can be packed into.
+const (
+	ArchiveTarGz = "tar.gz"
+	ArchiveTarXz = "tar.xz"
+)
+
+// ChecksumFile lists the checksum of every file within a template
+// archive, and is the suffix of the checksum file kept beside it.
+const ChecksumFile = "checksums.sha256"
+
+// archiveSuffixes are the file suffixes of the archives templates can be
+// expanded from.
+var archiveSuffixes = []string{".tar.gz", ".tgz", ".tar.xz", ".txz", ".zip"}
+
+// IsValidArchiveFormat checks that the given format can be packed.
+func IsValidArchiveFormat(format string) bool {
+	return format == ArchiveTarGz || format == ArchiveTarXz
+}
+
+// IsArchive returns true if the template source is an archive, either
+// on disk or at an http(s) URL.
+func IsArchive(source string) bool {
+	for _, suffix := range archiveSuffixes {
+		if strings.HasSuffix(strings.ToLower(source), suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isRemoteArchive returns true if the archive is downloaded.
+func isRemoteArchive(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// fileChecksum returns the hex encoded sha256 of the file.
+func fileChecksum(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// packFiles lists the files packed from the template directory, the
+// manifest first and then every other file in order, such as the
+// partials the template includes. Git metadata, the template's test
+// cases and the archive being written are left out.
+func packFiles(root string, manifest string, output string) ([]string, error) {
+	files := []string{manifest}
+	output, _ = filepath.Abs(output)
+
+	err := filepath.Walk(root, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(root, file)
+		rel = filepath.ToSlash(rel)
+
+		if info.IsDir() {
+			if info.Name() == ".git" || rel == TestDirectory {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		abs, _ := filepath.Abs(file)
+		if rel == manifest || rel == ChecksumFile || abs == output || abs == output+".sha256" {
+			return nil
+		}
+
+		files = append(files, rel)
+
+		return nil
+	})
+
+	sort.Strings(files[1:])
+
+	return files, err
+}
+
+// LatestVersion returns the newest semver tag of the template's
+// repository, or an empty string if it has none.
+func (temp Template) LatestVersion() string {
+	repo, err := git.PlainOpen(temp.TemplateDirectory.Path)
+	if err != nil {
+		return ""
+	}
+
+	tag, err := resolveVersionRange(repo, ">=0.0.0")
+	if err != nil {
+		return ""
+	}
+
+	return tag
+}
+
+// Pack writes the template's manifest and files into an
+// archive of the given format, along with a checksum of every file. The
+// archive's own checksum is written beside it and returned.
+func (temp Template) Pack(output string, format string) (string, error) {
+	root := temp.TemplateDirectory.Path
+
+	files, err := packFiles(root, filepath.Base(temp.TemplateDirectory.TemplatePath()), output)
+	if err != nil {
+		return "", err
+	}
+
+	var checksums strings.Builder
+	for _, file := range files {
+		sum, err := fileChecksum(path.Join(root, file))
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(&checksums, "%s  %s\n", sum, file)
+	}
+
+	out, err := os.Create(output)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	var compressed io.WriteCloser
+	if format == ArchiveTarXz {
+		compressed, err = xz.NewWriter(out)
+	} else {
+		compressed = gzip.NewWriter(out)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	tw := tar.NewWriter(compressed)
+
+	for _, file := range files {
+		if err := addTarFile(tw, root, file); err != nil {
+			return "", err
+		}
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Name: ChecksumFile,
+		Mode: 0644,
+		Size: int64(checksums.Len()),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := tw.Write([]byte(checksums.String())); err != nil {
+		return "", err
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+
+	if err := compressed.Close(); err != nil {
+		return "", err
+	}
+
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+
+	sum, err := fileChecksum(output)
+	if err != nil {
+		return "", err
+	}
+
+	line := fmt.Sprintf("%s  %s\n", sum, filepath.Base(output))
+
+	return sum, ioutil.WriteFile(output+".sha256", []byte(line), 0644)
+}
+
+// addTarFile writes the file within the root into the tar archive.
+func addTarFile(tw *tar.Writer, root string, file string) error {
+	info, err := os.Stat(path.Join(root, file))
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+
+	header.Name = file
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	f, err := os.Open(path.Join(root, file))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(tw, f)
+
+	return err
+}
+
+// archiveTarget returns where the archived file is unpacked within the
+// destination, refusing paths that would escape it.
+func archiveTarget(dest string, name string) (string, error) {
+	target := filepath.Join(dest, filepath.FromSlash(name))
+	if target != dest && !strings.HasPrefix(target, dest+string(os.PathSeparator)) {
+		return "", fmt.Errorf("the archive contains the unsafe path %s", name)
+	}
+
+	return target, nil
+}
+
+// writeArchiveFile writes an unpacked file.
+func writeArchiveFile(target string, mode os.FileMode, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+
+	return err
+}
+
+// unpackTar unpacks the tar stream into the destination.
+func unpackTar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := archiveTarget(dest, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, os.ModePerm)
+		case tar.TypeReg, tar.TypeRegA:
+			err = writeArchiveFile(target, os.FileMode(header.Mode), tr)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// unpackZip unpacks the zip archive into the destination.
+func unpackZip(file string, dest string) error {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		target, err := archiveTarget(dest, f.Name)
+		if err != nil {
+			return err
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, os.ModePerm); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return err
+		}
+
+		err = writeArchiveFile(target, f.Mode(), r)
+		r.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unpackArchive unpacks the archive into the destination, based on the
+// suffix of its name.
+func unpackArchive(file string, name string, dest string) error {
+	name = strings.ToLower(name)
+	if strings.HasSuffix(name, ".zip") {
+		return unpackZip(file, dest)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader
+	if strings.HasSuffix(name, ".tar.xz") || strings.HasSuffix(name, ".txz") {
+		r, err = xz.NewReader(bufio.NewReader(f))
+	} else {
+		r, err = gzip.NewReader(f)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return unpackTar(r, dest)
+}
+
+// verifyChecksums checks every file listed within the unpacked
+// archive's checksum file.
+func verifyChecksums(dir string) error {
+	data, err := ioutil.ReadFile(path.Join(dir, ChecksumFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		sum, err := fileChecksum(path.Join(dir, fields[1]))
+		if err != nil || sum != fields[0] {
+			return fmt.Errorf("the checksum of %s does not match the archive", fields[1])
+		}
+	}
+
+	return nil
+}
+
+// download fetches the URL into a temporary file, returning its path.
+// An empty path is returned if the URL is not found.
+func download(url string) (string, error) {
+	res, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching %s returned %s", url, res.Status)
+	}
+
+	f, err := ioutil.TempFile("", "lavra-template-archive")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, res.Body)
+
+	return f.Name(), err
+}
+
+// verifyArchiveChecksum checks the archive against the checksum file
+// published beside it. Archives downloaded over http(s) must publish
+// one, while archives on disk are checked when there is one.
+func verifyArchiveChecksum(archive string, source string) error {
+	sumFile := source + ".sha256"
+	if isRemoteArchive(source) {
+		downloaded, err := download(sumFile)
+		if err != nil {
+			return err
+		}
+
+		if downloaded == "" {
+			return fmt.Errorf("no checksum file was found at %s, archives downloaded over http(s) must publish the `.sha256` file written by `template pack`", sumFile)
+		}
+		defer os.Remove(downloaded)
+
+		sumFile = downloaded
+	}
+
+	data, err := ioutil.ReadFile(sumFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return errors.New("the archive checksum file is empty")
+	}
+
+	sum, err := fileChecksum(archive)
+	if err != nil {
+		return err
+	}
+
+	if sum != fields[0] {
+		return errors.New("the checksum of the archive does not match " + path.Base(source) + ".sha256")
+	}
+
+	return nil
+}
+
+// FetchArchive downloads the template archive if needed, verifies it and
+// unpacks it into the cache, replacing the previously unpacked version.
+func (temp Template) FetchArchive() {
+	spin := util.Spin("Unpacking template archive")
+
+	archive := temp.From
+	if isRemoteArchive(temp.From) {
+		downloaded, err := download(temp.From)
+		cmdutil.CheckCommandError(err, "downloading template archive")
+
+		if downloaded == "" {
+			cmdutil.ExitWithMessage("The template archive was not found at " + temp.From + ".")
+		}
+		defer os.Remove(downloaded)
+
+		archive = downloaded
+	}
+
+	err := verifyArchiveChecksum(archive, temp.From)
+	cmdutil.CheckCommandError(err, "verifying template archive")
+
+	storePath := temp.TemplateDirectory.Path
+	err = os.RemoveAll(storePath)
+	cmdutil.CheckCommandError(err, "clearing cached template archive")
+
+	err = unpackArchive(archive, temp.From, storePath)
+	cmdutil.CheckCommandError(err, "unpacking template archive")
+
+	err = verifyChecksums(storePath)
+	cmdutil.CheckCommandError(err, "verifying template archive")
+
+	if !temp.TemplateDirectory.IsTemplate() {
+		cmdutil.ExitWithMessage("The template archive does not contain a template manifest.")
+	}
+
+	temp.markFetched()
+	spin.Done()
+}
@@ -0,0 +1,311 @@
+package tmpl
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/kevinburke/ssh_config"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
+)
+
+// Auth methods used to fetch templates from a remote host.
+const (
+	AuthSSHAgent = "ssh-agent"
+	AuthSSHKey   = "ssh-key"
+	AuthToken    = "token"
+	AuthNetrc    = "netrc"
+	AuthNone     = "none"
+)
+
+// hostTokenEnv maps well known hosts to the environment variable
+// holding their access token.
+var hostTokenEnv = map[string]string{
+	"github.com": "GITHUB_TOKEN",
+	"gitlab.com": "GITLAB_TOKEN",
+}
+
+// defaultKeyFiles are tried, in order, when no key file is configured
+// for an SSH host.
+var defaultKeyFiles = []string{"~/.ssh/id_ed25519", "~/.ssh/id_ecdsa", "~/.ssh/id_rsa"}
+
+// HostAuth configures how templates are fetched from a remote host,
+// set under `templates.auth.<host>`. Without a Method, SSH remotes use
+// the SSH agent or a key file, and HTTPS remotes use a token from the
+// environment or `.netrc`.
+type HostAuth struct {
+	Method        string `mapstructure:"method"`
+	Username      string `mapstructure:"username"`
+	Token         string `mapstructure:"token"`
+	TokenEnv      string `mapstructure:"tokenEnv"`
+	KeyFile       string `mapstructure:"keyFile"`
+	PassphraseEnv string `mapstructure:"passphraseEnv"`
+}
+
+// GetHostAuth returns the auth configured for the host, and whether the
+// host is listed under `templates.auth` at all.
+func GetHostAuth(host string) (HostAuth, bool, error) {
+	var hosts map[string]HostAuth
+	if err := viper.UnmarshalKey("templates.auth", &hosts); err != nil {
+		return HostAuth{}, false, err
+	}
+
+	for name, auth := range hosts {
+		if strings.EqualFold(name, host) {
+			return auth, true, nil
+		}
+	}
+
+	return HostAuth{}, false, nil
+}
+
+// sshUser returns the user to connect to the SSH host as.
+func sshUser(endpoint *transport.Endpoint, auth HostAuth) string {
+	if auth.Username != "" {
+		return auth.Username
+	}
+
+	if endpoint.User != "" {
+		return endpoint.User
+	}
+
+	if user, _ := ssh_config.GetStrict(endpoint.Host, "User"); user != "" {
+		return user
+	}
+
+	return "git"
+}
+
+// sshKeyFile returns the key file for the SSH host, taken from the
+// config, the host's IdentityFile within `~/.ssh/config`, or the
+// default key files.
+func sshKeyFile(host string, auth HostAuth) string {
+	candidates := defaultKeyFiles
+	if identity, _ := ssh_config.GetStrict(host, "IdentityFile"); identity != "" {
+		candidates = append([]string{identity}, candidates...)
+	}
+
+	if auth.KeyFile != "" {
+		candidates = []string{auth.KeyFile}
+	}
+
+	for _, candidate := range candidates {
+		file, _ := homedir.Expand(candidate)
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+
+	return ""
+}
+
+// sshKeyAuth authenticates with the key file for the SSH host.
+func sshKeyAuth(endpoint *transport.Endpoint, auth HostAuth) (transport.AuthMethod, error) {
+	file := sshKeyFile(endpoint.Host, auth)
+	if file == "" {
+		return nil, fmt.Errorf("no SSH key file was found for %s", endpoint.Host)
+	}
+
+	return ssh.NewPublicKeysFromFile(sshUser(endpoint, auth), file, os.Getenv(auth.PassphraseEnv))
+}
+
+// hostToken returns the access token for the host, taken from the
+// config, the configured environment variable or the host's well known
+// environment variable. The generic `LAVRA_GIT_TOKEN` is only sent to
+// hosts listed under `templates.auth`, rather than to any host a
+// template or registry names.
+func hostToken(host string, auth HostAuth, listed bool) string {
+	if auth.Token != "" {
+		return auth.Token
+	}
+
+	envs := []string{auth.TokenEnv, hostTokenEnv[strings.ToLower(host)]}
+	if listed {
+		envs = append(envs, "LAVRA_GIT_TOKEN")
+	}
+
+	for _, env := range envs {
+		if env == "" {
+			continue
+		}
+
+		if token := os.Getenv(env); token != "" {
+			return token
+		}
+	}
+
+	return ""
+}
+
+// tokenAuth authenticates with an access token as the password.
+func tokenAuth(auth HostAuth, token string) transport.AuthMethod {
+	username := auth.Username
+	if username == "" {
+		username = "git"
+	}
+
+	return &http.BasicAuth{Username: username, Password: token}
+}
+
+// netrcPath returns the path of the `.netrc` file.
+func netrcPath() string {
+	if file := os.Getenv("NETRC"); file != "" {
+		return file
+	}
+
+	home, _ := homedir.Dir()
+
+	return path.Join(home, ".netrc")
+}
+
+// netrcLogin returns the login and password for the machine within the
+// `.netrc` file, falling back to its default entry.
+func netrcLogin(machine string) (string, string, bool) {
+	file, err := os.Open(netrcPath())
+	if err != nil {
+		return "", "", false
+	}
+	defer file.Close()
+
+	var fields []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields = append(fields, strings.Fields(line)...)
+	}
+
+	var login, password string
+	matched, found := false, false
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine", "default":
+			if matched {
+				return login, password, found
+			}
+
+			if fields[i] == "default" {
+				matched = true
+			} else if i+1 < len(fields) {
+				i++
+				matched = strings.EqualFold(fields[i], machine)
+			}
+		case "login", "password":
+			if i+1 >= len(fields) {
+				continue
+			}
+
+			if matched {
+				found = true
+				if fields[i] == "login" {
+					login = fields[i+1]
+				} else {
+					password = fields[i+1]
+				}
+			}
+
+			i++
+		}
+	}
+
+	return login, password, matched && found
+}
+
+// netrcAuth authenticates with the host's `.netrc` entry.
+func netrcAuth(host string) (transport.AuthMethod, bool) {
+	login, password, ok := netrcLogin(host)
+	if !ok {
+		return nil, false
+	}
+
+	return &http.BasicAuth{Username: login, Password: password}, true
+}
+
+// RemoteAuth returns the auth method used to fetch from the remote, or
+// nil if the remote is fetched anonymously.
+func RemoteAuth(remote string) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	if endpoint.Protocol != "ssh" && endpoint.Protocol != "http" && endpoint.Protocol != "https" {
+		return nil, nil
+	}
+
+	auth, listed, err := GetHostAuth(endpoint.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	// Credentials are never sent in the clear.
+	if endpoint.Protocol == "http" {
+		if auth.Method == AuthToken || auth.Method == AuthNetrc {
+			return nil, fmt.Errorf("credentials are only sent to %s over https, not http", endpoint.Host)
+		}
+
+		return nil, nil
+	}
+
+	switch auth.Method {
+	case AuthNone:
+		return nil, nil
+	case AuthSSHAgent:
+		return ssh.NewSSHAgentAuth(sshUser(endpoint, auth))
+	case AuthSSHKey:
+		return sshKeyAuth(endpoint, auth)
+	case AuthToken:
+		token := hostToken(endpoint.Host, auth, listed)
+		if token == "" {
+			return nil, fmt.Errorf("no access token was found for %s", endpoint.Host)
+		}
+
+		return tokenAuth(auth, token), nil
+	case AuthNetrc:
+		method, ok := netrcAuth(endpoint.Host)
+		if !ok {
+			return nil, fmt.Errorf("no .netrc entry was found for %s", endpoint.Host)
+		}
+
+		return method, nil
+	case "":
+	default:
+		return nil, fmt.Errorf("`%s` is not a valid auth method for %s", auth.Method, endpoint.Host)
+	}
+
+	if endpoint.Protocol == "ssh" {
+		if os.Getenv("SSH_AUTH_SOCK") != "" {
+			return ssh.NewSSHAgentAuth(sshUser(endpoint, auth))
+		}
+
+		if sshKeyFile(endpoint.Host, auth) != "" {
+			return sshKeyAuth(endpoint, auth)
+		}
+
+		return nil, nil
+	}
+
+	if token := hostToken(endpoint.Host, auth, listed); token != "" {
+		return tokenAuth(auth, token), nil
+	}
+
+	if method, ok := netrcAuth(endpoint.Host); ok {
+		return method, nil
+	}
+
+	return nil, nil
+}
+
+// Auth returns the auth method used to fetch the template.
+func (temp Template) Auth() (transport.AuthMethod, error) {
+	return RemoteAuth(temp.GetSafeRemote())
+}
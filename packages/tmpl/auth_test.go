@@ -0,0 +1,56 @@
+package tmpl
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+)
+
+func TestRemoteAuthToken(t *testing.T) {
+	os.Setenv("LAVRA_GIT_TOKEN", "secret")
+	defer os.Unsetenv("LAVRA_GIT_TOKEN")
+
+	os.Setenv("NETRC", os.DevNull)
+	defer os.Unsetenv("NETRC")
+
+	viper.Set("templates.auth", map[string]interface{}{
+		"git.example.com": map[string]interface{}{"username": "oauth2"},
+	})
+	defer viper.Set("templates.auth", nil)
+
+	tests := []struct {
+		remote string
+		token  bool
+	}{
+		{"https://git.example.com/org/repo.git", true},
+		{"https://other.example.com/org/repo.git", false},
+		{"http://git.example.com/org/repo.git", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.remote, func(t *testing.T) {
+			auth, err := RemoteAuth(test.remote)
+			if err != nil {
+				t.Fatalf("RemoteAuth(%q) returned %s", test.remote, err)
+			}
+
+			basic, ok := auth.(*http.BasicAuth)
+			if got := ok && basic.Password == "secret"; got != test.token {
+				t.Errorf("RemoteAuth(%q) sends the token = %v, want %v", test.remote, got, test.token)
+			}
+		})
+	}
+}
+
+func TestRemoteAuthTokenOverHTTP(t *testing.T) {
+	viper.Set("templates.auth", map[string]interface{}{
+		"git.example.com": map[string]interface{}{"method": AuthToken, "token": "secret"},
+	})
+	defer viper.Set("templates.auth", nil)
+
+	if _, err := RemoteAuth("http://git.example.com/org/repo.git"); err == nil {
+		t.Error("RemoteAuth() returned no error for a token over http")
+	}
+}
@@ -0,0 +1,236 @@
+package tmpl
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultCacheTTL is how long a fetched template is used from the cache
+// before its remote is checked again, unless `templates.cache.ttl` is
+// set.
+const DefaultCacheTTL = time.Hour
+
+// cacheMetaExt is the extension of the file kept beside each cached
+// template, describing where and when it was fetched from.
+const cacheMetaExt = ".yml"
+
+// IsOffline returns true when templates must be expanded from the cache
+// without network access, set with `--offline` or `templates.offline`.
+func IsOffline() bool {
+	return viper.GetBool("templates.offline")
+}
+
+// GetCacheTTL returns how long fetched templates are used from the
+// cache before their remote is checked again.
+func GetCacheTTL() time.Duration {
+	if viper.IsSet("templates.cache.ttl") {
+		return viper.GetDuration("templates.cache.ttl")
+	}
+
+	return DefaultCacheTTL
+}
+
+// CacheEntry is a template fetched into the cache.
+type CacheEntry struct {
+	Key     string    `yaml:"-"`
+	Path    string    `yaml:"-"`
+	Size    int64     `yaml:"-"`
+	Source  string    `yaml:"source"`
+	Commit  string    `yaml:"commit"`
+	Fetched time.Time `yaml:"fetched"`
+}
+
+// Fresh returns true if the entry was fetched within the cache TTL.
+func (entry CacheEntry) Fresh() bool {
+	return time.Since(entry.Fetched) < GetCacheTTL()
+}
+
+// metaPath returns the path of the file describing the cache entry.
+func metaPath(key string) string {
+	return path.Join(GetCachePath(), key+cacheMetaExt)
+}
+
+// loadCacheEntry loads the cache entry with the given key. Entries
+// cached before their fetch was recorded use the time the directory
+// was last modified.
+func loadCacheEntry(key string) (CacheEntry, error) {
+	entry := CacheEntry{
+		Key:  key,
+		Path: path.Join(GetCachePath(), key),
+	}
+
+	info, err := os.Stat(entry.Path)
+	if err != nil {
+		return entry, err
+	}
+
+	entry.Fetched = info.ModTime()
+
+	if data, err := ioutil.ReadFile(metaPath(key)); err == nil {
+		if err := yaml.Unmarshal(data, &entry); err != nil {
+			return entry, err
+		}
+	}
+
+	err = filepath.Walk(entry.Path, func(file string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			entry.Size += info.Size()
+		}
+
+		return err
+	})
+
+	return entry, err
+}
+
+// cacheEntry returns the cache entry of the template, if it has been
+// fetched.
+func (temp Template) cacheEntry() (CacheEntry, bool) {
+	entry, err := loadCacheEntry(temp.GetLocalPathByRemote())
+
+	return entry, err == nil
+}
+
+// IsCacheFresh returns true if the template was fetched within the
+// cache TTL, so its remote does not need to be checked.
+func (temp Template) IsCacheFresh() bool {
+	if temp.Local {
+		return false
+	}
+
+	entry, ok := temp.cacheEntry()
+
+	return ok && entry.Source != "" && entry.Fresh()
+}
+
+// markFetched records where and when the template was fetched from.
+func (temp Template) markFetched() {
+	commit, _ := temp.Commit()
+	entry := CacheEntry{
+		Source:  temp.Source(),
+		Commit:  commit,
+		Fetched: time.Now(),
+	}
+
+	if data, err := yaml.Marshal(entry); err == nil {
+		ioutil.WriteFile(metaPath(temp.GetLocalPathByRemote()), data, 0644)
+	}
+}
+
+// restoreCache checks the cached template out at the pinned ref, or at
+// the commit last fetched, in case a replay or upgrade left it at an
+// older commit.
+func (temp Template) restoreCache() {
+	if temp.Ref != "" {
+		temp.checkoutRef()
+
+		return
+	}
+
+	entry, ok := temp.cacheEntry()
+	if !ok || entry.Commit == "" {
+		return
+	}
+
+	if commit, err := temp.Commit(); err == nil && commit != entry.Commit {
+		temp.Checkout(entry.Commit)
+	}
+}
+
+// ListCache returns every template within the cache, most recently
+// fetched first.
+func ListCache() ([]CacheEntry, error) {
+	var entries []CacheEntry
+
+	infos, err := ioutil.ReadDir(GetCachePath())
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+
+		entry, err := loadCacheEntry(info.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Fetched.After(entries[j].Fetched) })
+
+	return entries, nil
+}
+
+// RemoveCacheEntry removes the cached template.
+func RemoveCacheEntry(entry CacheEntry) error {
+	if err := os.RemoveAll(entry.Path); err != nil {
+		return err
+	}
+
+	if err := os.Remove(metaPath(entry.Key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// PruneCache removes the templates not fetched within the given
+// duration, along with leftover files of removed templates.
+func PruneCache(olderThan time.Duration) ([]CacheEntry, error) {
+	var pruned []CacheEntry
+
+	entries, err := ListCache()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if time.Since(entry.Fetched) < olderThan {
+			continue
+		}
+
+		if err := RemoveCacheEntry(entry); err != nil {
+			return pruned, err
+		}
+
+		pruned = append(pruned, entry)
+	}
+
+	infos, err := ioutil.ReadDir(GetCachePath())
+	if err != nil && !os.IsNotExist(err) {
+		return pruned, err
+	}
+
+	for _, info := range infos {
+		key := strings.TrimSuffix(info.Name(), cacheMetaExt)
+		if info.IsDir() || key == info.Name() {
+			continue
+		}
+
+		if _, err := os.Stat(path.Join(GetCachePath(), key)); os.IsNotExist(err) {
+			os.Remove(metaPath(key))
+		}
+	}
+
+	return pruned, nil
+}
+
+// ClearCache removes every cached template.
+func ClearCache() error {
+	return os.RemoveAll(GetCachePath())
+}
@@ -0,0 +1,187 @@
+package tmpl
+
+import (
+	"path"
+	"strings"
+
+	"github.com/lavrahq/cli/packages/prompt"
+	"github.com/lavrahq/cli/util/cmdutil"
+)
+
+// partDir returns the directory of the template the Copy entry comes
+// from. Entries inherited from an extended or included template come
+// from that template's directory.
+func (temp Template) partDir(c Copy) string {
+	if c.Source == "" {
+		return temp.TemplateDirectory.Path
+	}
+
+	return c.Source
+}
+
+// copyRoot returns the directory the Copy entry copies from.
+func (temp Template) copyRoot(c Copy) string {
+	return path.Join(temp.partDir(c), "template")
+}
+
+// copySource returns the path within the template the Copy entry
+// copies from.
+func (temp Template) copySource(c Copy, from string) string {
+	return path.Join(temp.copyRoot(c), from)
+}
+
+// partSource returns the source of an extended or included template,
+// resolving relative paths against the template's own directory.
+func (temp Template) partSource(source string) string {
+	if strings.HasPrefix(source, ".") {
+		return path.Join(temp.TemplateDirectory.Path, source)
+	}
+
+	return source
+}
+
+// mergeQuestions appends the questions to the base questions. A
+// question with the same Name as a base question replaces it in place.
+func mergeQuestions(base []prompt.Question, questions []prompt.Question) []prompt.Question {
+	merged := append([]prompt.Question{}, base...)
+
+	for _, question := range questions {
+		replaced := false
+		for i, existing := range merged {
+			if existing.Name == question.Name {
+				merged[i] = question
+				replaced = true
+
+				break
+			}
+		}
+
+		if !replaced {
+			merged = append(merged, question)
+		}
+	}
+
+	return merged
+}
+
+// mergeComputed appends the computed answers to the base computed
+// answers. A computed answer with the same Name as a base computed
+// answer replaces it in place.
+func mergeComputed(base []prompt.Computed, computed []prompt.Computed) []prompt.Computed {
+	merged := append([]prompt.Computed{}, base...)
+
+	for _, c := range computed {
+		replaced := false
+		for i, existing := range merged {
+			if existing.Name == c.Name {
+				merged[i] = c
+				replaced = true
+
+				break
+			}
+		}
+
+		if !replaced {
+			merged = append(merged, c)
+		}
+	}
+
+	return merged
+}
+
+// mergeManifests merges the manifest over the base manifest. Questions
+// override base questions by Name, while copy, fill and hook entries
+// are appended after the base entries so the manifest's own files are
+// written last.
+func mergeManifests(base TemplateManifest, manifest TemplateManifest) TemplateManifest {
+	merged := manifest
+
+	if merged.Name == "" {
+		merged.Name = base.Name
+	}
+
+	if merged.Author == "" {
+		merged.Author = base.Author
+	}
+
+	if merged.Description == "" {
+		merged.Description = base.Description
+	}
+
+	if merged.Notes == "" {
+		merged.Notes = base.Notes
+	}
+
+	merged.Checks = nil
+	checked := make(map[string]bool)
+	for _, check := range append(append([]string{}, base.Checks...), manifest.Checks...) {
+		if !checked[check] {
+			checked[check] = true
+			merged.Checks = append(merged.Checks, check)
+		}
+	}
+
+	merged.Prompt.Questions = mergeQuestions(base.Prompt.Questions, manifest.Prompt.Questions)
+	merged.Prompt.Computed = mergeComputed(base.Prompt.Computed, manifest.Prompt.Computed)
+	merged.Copy = append(append([]Copy{}, base.Copy...), manifest.Copy...)
+	merged.Fill = append(append([]Fill{}, base.Fill...), manifest.Fill...)
+	merged.Hooks = Hooks{
+		PreCopy:  append(append([]Hook{}, base.Hooks.PreCopy...), manifest.Hooks.PreCopy...),
+		PostCopy: append(append([]Hook{}, base.Hooks.PostCopy...), manifest.Hooks.PostCopy...),
+		PreFill:  append(append([]Hook{}, base.Hooks.PreFill...), manifest.Hooks.PreFill...),
+		PostFill: append(append([]Hook{}, base.Hooks.PostFill...), manifest.Hooks.PostFill...),
+	}
+
+	return merged
+}
+
+// compose merges the templates the manifest extends and includes into
+// it. The extended template comes first, then each included template in
+// order, then the manifest itself. Each part is made and fetched like
+// any other template, and may itself extend or include templates. Parts
+// pinned to a commit are checked out at it rather than their latest
+// version. Every part is returned along with the commit it was composed
+// from, nested parts first.
+func (temp Template) compose(manifest TemplateManifest, loading map[string]bool) (TemplateManifest, []RecordPart) {
+	parts := manifest.Include
+	if manifest.Extends != "" {
+		parts = append([]string{manifest.Extends}, parts...)
+	}
+
+	if len(parts) == 0 {
+		return manifest, nil
+	}
+
+	loading[temp.TemplateDirectory.Path] = true
+	defer delete(loading, temp.TemplateDirectory.Path)
+
+	var composed TemplateManifest
+	var recorded []RecordPart
+	for _, source := range parts {
+		resolved := temp.partSource(source)
+
+		pinned := resolved
+		if commit := temp.Pins[resolved]; commit != "" {
+			from, _ := SplitRef(resolved)
+			pinned = from + "@" + commit
+		}
+
+		part := Make(temp.Directory, pinned)
+		part.Pins = temp.Pins
+		part.Refresh = temp.Refresh
+		if loading[part.TemplateDirectory.Path] {
+			cmdutil.ExitWithMessage("The template `" + source + "` extends or includes itself.")
+		}
+
+		part.EnsureTemplateIsFetched()
+		part = part.loadManifest(loading)
+
+		commit, _ := part.Commit()
+		recorded = append(recorded, part.Parts...)
+		recorded = append(recorded, RecordPart{Source: resolved, Commit: commit})
+
+		composed = mergeManifests(composed, part.Manifest)
+	}
+
+	return mergeManifests(composed, manifest), recorded
+}
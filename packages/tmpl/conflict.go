@@ -0,0 +1,172 @@
+package tmpl
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/lavrahq/cli/packages/when"
+	"github.com/lavrahq/cli/util"
+)
+
+// Conflict policies decide what happens when an expansion would write
+// over a file that already exists with different content.
+const (
+	ConflictOverwrite = "overwrite"
+	ConflictSkip      = "skip"
+	ConflictPrompt    = "prompt"
+	ConflictBackup    = "backup"
+	ConflictFail      = "fail"
+)
+
+// BackupSuffix is appended to files backed up by the backup policy.
+const BackupSuffix = ".orig"
+
+// IsValidConflictPolicy checks that the given policy is valid.
+func IsValidConflictPolicy(policy string) bool {
+	switch policy {
+	case
+		ConflictOverwrite,
+		ConflictSkip,
+		ConflictPrompt,
+		ConflictBackup,
+		ConflictFail:
+		return true
+	}
+
+	return false
+}
+
+// DefaultConflictPolicy returns `prompt` when asking questions in a
+// terminal and `fail` otherwise, such as for `--no-input` or
+// `--answers` runs.
+func DefaultConflictPolicy(noInput bool) string {
+	if !noInput && util.IsInteractive() {
+		return ConflictPrompt
+	}
+
+	return ConflictFail
+}
+
+// Conflict records a project file that an expansion would have
+// overwritten and how it was resolved.
+type Conflict struct {
+	File       string
+	Resolution string
+}
+
+// ConflictLog collects the conflicts hit during an expansion.
+type ConflictLog struct {
+	Conflicts []Conflict
+}
+
+// Add records the resolution of a conflicting file.
+func (log *ConflictLog) Add(file string, resolution string) {
+	log.Conflicts = append(log.Conflicts, Conflict{File: file, Resolution: resolution})
+}
+
+// Resolution returns how the conflicting file was resolved, or an empty
+// string if the file did not conflict.
+func (log *ConflictLog) Resolution(file string) string {
+	for _, conflict := range log.Conflicts {
+		if conflict.File == file {
+			return conflict.Resolution
+		}
+	}
+
+	return ""
+}
+
+// Failed returns the files resolved with the fail policy.
+func (log *ConflictLog) Failed() []string {
+	var files []string
+
+	for _, conflict := range log.Conflicts {
+		if conflict.Resolution == ConflictFail {
+			files = append(files, conflict.File)
+		}
+	}
+
+	return files
+}
+
+// Summary lists every conflicting file and what was done with it.
+func (log *ConflictLog) Summary() string {
+	var lines []string
+
+	for _, conflict := range log.Conflicts {
+		lines = append(lines, fmt.Sprintf("  %-10s /%s", conflict.Resolution, conflict.File))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// filledContent returns the content the copied file is left with once
+// the expansion is filled, applying the same fill as Fill would.
+func (temp Template) filledContent(c Copy, f copyFile, env WhenEnvironment) ([]byte, error) {
+	fillEnv := env
+	fillEnv.Item, fillEnv.Index = nil, 0
+
+	for _, fill := range temp.Manifest.Fill {
+		fillEnv.Vars = fill.Vars
+
+		if !when.ImplicitlyTrue(fill.When) && !when.True(fill.When, fillEnv) {
+			continue
+		}
+
+		matched, err := temp.fillMatches(fill, fillEnv, f.File)
+		if err != nil {
+			return nil, err
+		}
+
+		if matched {
+			if f.Item != nil {
+				fillEnv.Item, fillEnv.Index = f.Item.Item, f.Item.Index
+			}
+
+			return renderFile(temp, f.Dir, f.Source, fillEnv)
+		}
+	}
+
+	if c.Fill {
+		env.Vars = nil
+
+		return renderFile(temp, f.Dir, f.Source, env)
+	}
+
+	return ioutil.ReadFile(f.Source)
+}
+
+// conflicts returns true if copying the file would change an existing
+// project file. Files that are filled are compared once rendered.
+func (temp Template) conflicts(c Copy, f copyFile, env WhenEnvironment) bool {
+	current, err := ioutil.ReadFile(f.Target)
+	if err != nil {
+		return false
+	}
+
+	next, err := temp.filledContent(c, f, env)
+	if err != nil {
+		return true
+	}
+
+	return !bytes.Equal(current, next)
+}
+
+// askConflictResolution asks the user how to resolve a conflicting file.
+func askConflictResolution(file string) string {
+	resolution := ConflictFail
+
+	err := survey.AskOne(&survey.Select{
+		Message: fmt.Sprintf("/%s already exists and differs from the template:", file),
+		Options: []string{ConflictOverwrite, ConflictSkip, ConflictBackup, ConflictFail},
+		Default: ConflictSkip,
+	}, &resolution)
+	if err != nil {
+		return ConflictFail
+	}
+
+	return resolution
+}
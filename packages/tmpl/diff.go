@@ -0,0 +1,274 @@
+package tmpl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// diffContext is the number of unchanged lines shown around each
+// change within a unified diff.
+const diffContext = 3
+
+// lineOp is a single line of a line-based diff.
+type lineOp struct {
+	Type diffmatchpatch.Operation
+	Line string
+}
+
+// hunk replaces the base lines from start to end with lines.
+type hunk struct {
+	start int
+	end   int
+	lines []string
+}
+
+// splitLines splits the text into lines, keeping the line endings.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines returns the line-based diff between a and b.
+func diffLines(a, b string) []lineOp {
+	dmp := diffmatchpatch.New()
+	runesA, runesB, lineArray := dmp.DiffLinesToRunes(a, b)
+
+	var ops []lineOp
+	for _, d := range dmp.DiffMainRunes(runesA, runesB, false) {
+		for _, r := range d.Text {
+			ops = append(ops, lineOp{Type: d.Type, Line: lineArray[r]})
+		}
+	}
+
+	return ops
+}
+
+// hunks groups the line diff into the hunks that turn a into b.
+func hunks(ops []lineOp) []hunk {
+	var result []hunk
+	var current *hunk
+	pos := 0
+
+	for _, op := range ops {
+		switch op.Type {
+		case diffmatchpatch.DiffEqual:
+			if current != nil {
+				result = append(result, *current)
+				current = nil
+			}
+
+			pos++
+		case diffmatchpatch.DiffDelete:
+			if current == nil {
+				current = &hunk{start: pos, end: pos}
+			}
+
+			pos++
+			current.end = pos
+		case diffmatchpatch.DiffInsert:
+			if current == nil {
+				current = &hunk{start: pos, end: pos}
+			}
+
+			current.lines = append(current.lines, op.Line)
+		}
+	}
+
+	if current != nil {
+		result = append(result, *current)
+	}
+
+	return result
+}
+
+// applyHunks applies the hunks to the base lines between start and end.
+func applyHunks(base []string, start, end int, changes []hunk) []string {
+	var lines []string
+	pos := start
+
+	for _, h := range changes {
+		lines = append(lines, base[pos:h.start]...)
+		lines = append(lines, h.lines...)
+		pos = h.end
+	}
+
+	return append(lines, base[pos:end]...)
+}
+
+// terminated ensures the last line ends with a newline so that
+// conflict markers always start on their own line.
+func terminated(lines []string) []string {
+	if len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		lines = append(lines[:len(lines)-1:len(lines)-1], lines[len(lines)-1]+"\n")
+	}
+
+	return lines
+}
+
+// Merge3 merges the changes made from base to ours and from base to
+// theirs line by line. Overlapping changes that differ are wrapped in
+// conflict markers and reported by the returned boolean.
+func Merge3(base, ours, theirs string) (string, bool) {
+	baseLines := splitLines(base)
+	oursHunks := hunks(diffLines(base, ours))
+	theirsHunks := hunks(diffLines(base, theirs))
+
+	var merged []string
+	conflicted := false
+	pos, i, j := 0, 0, 0
+
+	for i < len(oursHunks) || j < len(theirsHunks) {
+		var start, end int
+
+		if j >= len(theirsHunks) || (i < len(oursHunks) && oursHunks[i].start <= theirsHunks[j].start) {
+			start, end = oursHunks[i].start, oursHunks[i].end
+		} else {
+			start, end = theirsHunks[j].start, theirsHunks[j].end
+		}
+
+		var oursRegion, theirsRegion []hunk
+		for extended := true; extended; {
+			extended = false
+
+			for i < len(oursHunks) && oursHunks[i].start <= end {
+				if oursHunks[i].end > end {
+					end = oursHunks[i].end
+				}
+
+				oursRegion = append(oursRegion, oursHunks[i])
+				extended = true
+				i++
+			}
+
+			for j < len(theirsHunks) && theirsHunks[j].start <= end {
+				if theirsHunks[j].end > end {
+					end = theirsHunks[j].end
+				}
+
+				theirsRegion = append(theirsRegion, theirsHunks[j])
+				extended = true
+				j++
+			}
+		}
+
+		merged = append(merged, baseLines[pos:start]...)
+		pos = end
+
+		oursLines := applyHunks(baseLines, start, end, oursRegion)
+		theirsLines := applyHunks(baseLines, start, end, theirsRegion)
+
+		switch {
+		case len(theirsRegion) == 0:
+			merged = append(merged, oursLines...)
+		case len(oursRegion) == 0:
+			merged = append(merged, theirsLines...)
+		case strings.Join(oursLines, "") == strings.Join(theirsLines, ""):
+			merged = append(merged, oursLines...)
+		default:
+			conflicted = true
+
+			merged = terminated(merged)
+			merged = append(merged, "<<<<<<< project\n")
+			merged = append(merged, terminated(oursLines)...)
+			merged = append(merged, "=======\n")
+			merged = append(merged, terminated(theirsLines)...)
+			merged = append(merged, ">>>>>>> template\n")
+		}
+	}
+
+	merged = append(merged, baseLines[pos:]...)
+
+	return strings.Join(merged, ""), conflicted
+}
+
+// UnifiedDiff returns the unified diff between a and b, or an empty
+// string if they are the same.
+func UnifiedDiff(fromName, toName, a, b string) string {
+	ops := diffLines(a, b)
+
+	// Mark every op within the context of a change.
+	include := make([]bool, len(ops))
+	changed := false
+	for k, op := range ops {
+		if op.Type == diffmatchpatch.DiffEqual {
+			continue
+		}
+
+		changed = true
+		for c := k - diffContext; c <= k+diffContext; c++ {
+			if c >= 0 && c < len(ops) {
+				include[c] = true
+			}
+		}
+	}
+
+	if !changed {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	lineA, lineB := 0, 0
+	for k := 0; k < len(ops); {
+		if !include[k] {
+			if ops[k].Type != diffmatchpatch.DiffInsert {
+				lineA++
+			}
+
+			if ops[k].Type != diffmatchpatch.DiffDelete {
+				lineB++
+			}
+
+			k++
+
+			continue
+		}
+
+		startA, startB, countA, countB := lineA, lineB, 0, 0
+		var body strings.Builder
+
+		for ; k < len(ops) && include[k]; k++ {
+			prefix := " "
+
+			switch ops[k].Type {
+			case diffmatchpatch.DiffEqual:
+				countA++
+				countB++
+			case diffmatchpatch.DiffDelete:
+				prefix = "-"
+				countA++
+			case diffmatchpatch.DiffInsert:
+				prefix = "+"
+				countB++
+			}
+
+			body.WriteString(prefix + ops[k].Line)
+			if !strings.HasSuffix(ops[k].Line, "\n") {
+				body.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		lineA += countA
+		lineB += countB
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n%s", hunkRange(startA, countA), hunkRange(startB, countB), body.String())
+	}
+
+	return out.String()
+}
+
+// hunkRange formats the line range of a unified diff hunk.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,94 @@
+package tmpl
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name       string
+		base       string
+		ours       string
+		theirs     string
+		merged     string
+		conflicted bool
+	}{
+		{
+			name:   "unchanged",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nb\nc\n",
+			merged: "a\nb\nc\n",
+		},
+		{
+			name:   "only ours changed",
+			base:   "a\nb\nc\n",
+			ours:   "a\nB\nc\n",
+			theirs: "a\nb\nc\n",
+			merged: "a\nB\nc\n",
+		},
+		{
+			name:   "only theirs changed",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nb\nC\n",
+			merged: "a\nb\nC\n",
+		},
+		{
+			name:   "separate changes",
+			base:   "a\nb\nc\nd\ne\n",
+			ours:   "A\nb\nc\nd\ne\n",
+			theirs: "a\nb\nc\nd\nE\n",
+			merged: "A\nb\nc\nd\nE\n",
+		},
+		{
+			name:   "same change on both sides",
+			base:   "a\nb\nc\n",
+			ours:   "a\nX\nc\n",
+			theirs: "a\nX\nc\n",
+			merged: "a\nX\nc\n",
+		},
+		{
+			name:   "lines added at the end",
+			base:   "a\n",
+			ours:   "a\n",
+			theirs: "a\nb\n",
+			merged: "a\nb\n",
+		},
+		{
+			name:   "lines deleted by theirs",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nc\n",
+			merged: "a\nc\n",
+		},
+		{
+			name:       "overlapping changes",
+			base:       "a\nb\nc\n",
+			ours:       "a\nours\nc\n",
+			theirs:     "a\ntheirs\nc\n",
+			merged:     "a\n<<<<<<< project\nours\n=======\ntheirs\n>>>>>>> template\nc\n",
+			conflicted: true,
+		},
+		{
+			name:       "conflict without a trailing newline",
+			base:       "a",
+			ours:       "b",
+			theirs:     "c",
+			merged:     "<<<<<<< project\nb\n=======\nc\n>>>>>>> template\n",
+			conflicted: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, conflicted := Merge3(test.base, test.ours, test.theirs)
+
+			if merged != test.merged {
+				t.Errorf("Merge3() merged = %q, want %q", merged, test.merged)
+			}
+
+			if conflicted != test.conflicted {
+				t.Errorf("Merge3() conflicted = %v, want %v", conflicted, test.conflicted)
+			}
+		})
+	}
+}
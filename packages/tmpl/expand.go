@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
 	"text/template"
 
 	"github.com/lavrahq/cli/packages/prompt"
@@ -14,74 +16,352 @@ import (
 )
 
 // WhenEnvironment provides the available fields for `when`
-// evaluations.
+// evaluations. Item and Index are set while expanding a Copy entry once
+// for each item of a Group answer.
 type WhenEnvironment struct {
 	Answers  prompt.AnswerMap
 	Template TemplateManifest
 	Vars     map[string]interface{}
 	Env      map[string]string
+	Item     prompt.AnswerMap
+	Index    int
 }
 
-func copyExpansion(temp Template, c Copy) {
-	var into = ""
+// CopyItem is the Group item a file was copied for, and its index.
+type CopyItem struct {
+	Item  prompt.AnswerMap
+	Index int
+}
+
+// CopyLog collects the project files written during an expansion, along
+// with the directory of the template each file was copied from and the
+// Group item each file was copied for.
+type CopyLog struct {
+	Files     []string
+	Templated []string
+	Dirs      map[string]string
+	Items     map[string]CopyItem
+}
+
+// Add records a copied file, the directory of the template it was
+// copied from, and whether it should be filled.
+func (log *CopyLog) Add(file string, dir string, templated bool) {
+	if log.Dirs == nil {
+		log.Dirs = make(map[string]string)
+	}
+
+	log.Files = append(log.Files, file)
+	log.Dirs[file] = dir
+
+	if templated {
+		log.Templated = append(log.Templated, file)
+	}
+}
 
-	if c.Into != "" {
-		into = c.Into
+// AddItem records the Group item the file was copied for, so it is
+// filled with the item.
+func (log *CopyLog) AddItem(file string, item CopyItem) {
+	if log.Items == nil {
+		log.Items = make(map[string]CopyItem)
 	}
 
+	log.Items[file] = item
+}
+
+// cleanFile normalizes a project file path as given in the manifest.
+func cleanFile(file string) string {
+	return path.Clean(strings.TrimPrefix(file, "/"))
+}
+
+// copyFile is a single file copied by a Copy entry from the template
+// directory Dir, for the Group item the entry is expanded for, if any.
+type copyFile struct {
+	Dir    string
+	Source string
+	Target string
+	File   string
+	Item   *CopyItem
+}
+
+// conflictPolicy returns the conflict policy for the Copy entry. The
+// entry's own policy, set by the template's author for files such as
+// user-owned config, wins over the policy given for the run. Runs that
+// never ask anything use the run's policy rather than prompt, and fail
+// without one.
+func (temp Template) conflictPolicy(c Copy) string {
+	canPrompt := !temp.NoInput && util.IsInteractive()
+
+	for _, policy := range []string{c.Conflict, temp.ConflictPolicy} {
+		if policy != "" && (policy != ConflictPrompt || canPrompt) {
+			return policy
+		}
+	}
+
+	return DefaultConflictPolicy(!canPrompt)
+}
+
+// planCopy lists the files copied by the Copy entry, resolving any
+// conflicts with existing project files. Conflicts under the prompt
+// policy are only asked about when ask is true.
+func planCopy(temp Template, c Copy, env WhenEnvironment, ask bool) []copyFile {
+	var files []copyFile
+
+	from, keep, err := renderPath(temp, c.From, env)
+	cmdutil.CheckCommandError(err, fmt.Sprintf("rendering copy from, %s", c.From))
+
+	into, keepInto, err := renderPath(temp, c.Into, env)
+	cmdutil.CheckCommandError(err, fmt.Sprintf("rendering copy into, %s", c.Into))
+
+	if !keep || !keepInto {
+		return files
+	}
+
+	source := temp.copySource(c, from)
+	if !withinDir(temp.copyRoot(c), source) {
+		cmdutil.ExitWithMessage(fmt.Sprintf("The copy from /%s renders to /%s, which is outside of the template.", c.From, from))
+	}
+
+	target := path.Join(temp.Directory.Path, into)
+	if !withinDir(temp.Directory.Path, target) {
+		cmdutil.ExitWithMessage(fmt.Sprintf("The copy into /%s renders to /%s, which is outside of the project.", c.Into, into))
+	}
+
+	err = filepath.Walk(source, func(file string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, _ := filepath.Rel(source, file)
+		rel, keep, err := renderPath(temp, filepath.ToSlash(rel), env)
+		if err != nil || !keep {
+			return err
+		}
+
+		f := copyFile{
+			Dir:    temp.partDir(c),
+			Source: file,
+			Target: path.Join(target, rel),
+		}
+
+		if !withinDir(temp.Directory.Path, f.Target) {
+			return fmt.Errorf("/%s renders to a file outside of the project", rel)
+		}
+
+		f.File, _ = filepath.Rel(temp.Directory.Path, f.Target)
+		f.File = filepath.ToSlash(f.File)
+
+		if env.Item != nil {
+			f.Item = &CopyItem{Item: env.Item, Index: env.Index}
+		}
+
+		if temp.conflicts(c, f, env) {
+			resolution := temp.conflictPolicy(c)
+			if resolution == ConflictPrompt && ask {
+				resolution = askConflictResolution(f.File)
+			}
+
+			temp.Conflicts.Add(f.File, resolution)
+		}
+
+		files = append(files, f)
+
+		return nil
+	})
+	cmdutil.CheckCommandError(err, fmt.Sprintf("listing /%s", from))
+
+	return files
+}
+
+func copyExpansion(temp Template, c Copy, files []copyFile) {
 	spin := util.Spin(fmt.Sprintf(" + From /%s to /%s", c.From, c.Into))
-	err := copy.Copy(
-		path.Join(temp.TemplateDirectory.Path, "template", c.From),
-		path.Join(temp.Directory.Path, into),
-	)
 
-	if err != nil {
-		spin.Failed(err)
+	for _, f := range files {
+		resolution := temp.Conflicts.Resolution(f.File)
+		if resolution == ConflictSkip {
+			continue
+		}
+
+		if resolution == ConflictBackup {
+			if err := os.Rename(f.Target, f.Target+BackupSuffix); err != nil {
+				spin.Failed(err)
+
+				return
+			}
+		}
+
+		if err := copy.Copy(f.Source, f.Target); err != nil {
+			spin.Failed(err)
+
+			return
+		}
+
+		temp.Copied.Add(f.File, f.Dir, c.Fill)
+		if f.Item != nil {
+			temp.Copied.AddItem(f.File, *f.Item)
+		}
 	}
 
 	spin.Done()
 }
 
-func fillExpansion(temp Template, fill Fill, env WhenEnvironment) {
-	filePath := path.Join(temp.Directory.Path, fill.File)
+// fillExpansion fills the project file, including partials from the
+// template it was copied from.
+func fillExpansion(temp Template, file string, env WhenEnvironment) {
+	filePath := path.Join(temp.Directory.Path, file)
 
-	spin := util.Spin(fmt.Sprintf(" + Filling /%s", fill.File))
+	spin := util.Spin(fmt.Sprintf(" + Filling /%s", file))
 	defer spin.Done()
 
-	tmpl, err := template.ParseFiles(filePath)
-	cmdutil.CheckCommandError(err, fmt.Sprintf("parse template, %s", fill.File))
+	dir, ok := temp.Copied.Dirs[file]
+	if !ok {
+		dir = temp.TemplateDirectory.Path
+	}
+
+	tmpl, err := template.New(path.Base(filePath)).Funcs(temp.funcMapWithin(dir)).ParseFiles(filePath)
+	cmdutil.CheckCommandError(err, fmt.Sprintf("parse template, %s", file))
 
-	file, err := os.Create(filePath)
-	cmdutil.CheckCommandError(err, fmt.Sprintf("init template, %s", fill.File))
+	out, err := os.Create(filePath)
+	cmdutil.CheckCommandError(err, fmt.Sprintf("init template, %s", file))
 
-	err = tmpl.Execute(file, env)
-	cmdutil.CheckCommandError(err, fmt.Sprintf("execute template, %s", fill.File))
+	err = tmpl.Execute(out, env)
+	cmdutil.CheckCommandError(err, fmt.Sprintf("execute template, %s", file))
 
-	file.Close()
+	out.Close()
 }
 
-// Copy expands the template into the template's directory.
-func (temp Template) Copy() {
-	copySpinner := util.Spin("Copying Files")
-	copySpinner.Done()
+// fillMatches returns true if the Fill entry applies to the project
+// file.
+func (temp Template) fillMatches(f Fill, env WhenEnvironment, file string) (bool, error) {
+	if f.Glob == "" {
+		filled, keep, err := renderPath(temp, f.File, env)
+		if err != nil || !keep {
+			return false, err
+		}
 
-	for _, c := range temp.Manifest.Copy {
-		if when.ImplicitlyTrue(c.When) {
-			copyExpansion(temp, c)
+		return cleanFile(filled) == file, nil
+	}
 
-			continue
+	matched, err := MatchGlob(f.Glob, file)
+	if err != nil {
+		return false, err
+	}
+
+	for _, exclude := range f.Exclude {
+		excluded, err := MatchGlob(exclude, file)
+		if err != nil {
+			return false, err
 		}
 
-		env := WhenEnvironment{
-			Answers:  prompt.Answers[temp.Manifest.Name],
-			Template: temp.Manifest,
-			Env:      util.GetEnvMap(),
+		matched = matched && !excluded
+	}
+
+	return matched, nil
+}
+
+// fillFiles returns the project files the Fill entry applies to. A
+// glob only matches files copied from the template, never files that
+// were already within the project.
+func (temp Template) fillFiles(f Fill, env WhenEnvironment) []string {
+	if f.Glob == "" {
+		file, keep, err := renderPath(temp, f.File, env)
+		cmdutil.CheckCommandError(err, fmt.Sprintf("rendering fill file, %s", f.File))
+
+		if !keep {
+			return nil
+		}
+
+		if !withinDir(temp.Directory.Path, path.Join(temp.Directory.Path, cleanFile(file))) {
+			cmdutil.ExitWithMessage(fmt.Sprintf("The fill of /%s renders to /%s, which is outside of the project.", f.File, file))
 		}
 
-		if when.True(c.When, env) {
-			copyExpansion(temp, c)
+		return []string{cleanFile(file)}
+	}
+
+	var files []string
+	for _, file := range temp.Copied.Files {
+		matched, err := temp.fillMatches(f, env, file)
+		cmdutil.CheckCommandError(err, fmt.Sprintf("matching fill glob, %s", f.Glob))
+
+		if matched {
+			files = append(files, file)
 		}
 	}
+
+	return files
+}
+
+// copyEnvironments returns the environments the Copy entry is expanded
+// with: one for each item of the Group answer named by `each`, or the
+// env itself.
+func copyEnvironments(c Copy, env WhenEnvironment) []WhenEnvironment {
+	if c.Each == "" {
+		return []WhenEnvironment{env}
+	}
+
+	// Group questions skipped by their `when` have no items.
+	value := env.Answers[c.Each]
+	if value == nil {
+		return []WhenEnvironment{}
+	}
+
+	items, ok := value.([]prompt.AnswerMap)
+	if !ok {
+		cmdutil.ExitWithMessage(fmt.Sprintf("The copy entry from /%s expands each item of `%s`, which is not a Group answer.", c.From, c.Each))
+	}
+
+	envs := []WhenEnvironment{}
+	for i, item := range items {
+		itemEnv := env
+		itemEnv.Item = item
+		itemEnv.Index = i
+
+		envs = append(envs, itemEnv)
+	}
+
+	return envs
+}
+
+// planExpansion plans every Copy entry against the template's
+// directory, returning the entries expanded along with the files each
+// copies.
+func (temp Template) planExpansion(ask bool) ([]Copy, [][]copyFile) {
+	var entries []Copy
+	var plans [][]copyFile
+
+	env := WhenEnvironment{
+		Answers:  prompt.Answers[temp.Manifest.Name],
+		Template: temp.Manifest,
+		Env:      util.GetEnvMap(),
+	}
+
+	for _, c := range temp.Manifest.Copy {
+		for _, entryEnv := range copyEnvironments(c, env) {
+			if when.ImplicitlyTrue(c.When) || when.True(c.When, entryEnv) {
+				entries = append(entries, c)
+				plans = append(plans, planCopy(temp, c, entryEnv, ask))
+			}
+		}
+	}
+
+	return entries, plans
+}
+
+// Copy expands the template into the template's directory. Every
+// conflict is resolved before anything is written, so that the `fail`
+// policy never leaves a partial project behind.
+func (temp Template) Copy() {
+	copySpinner := util.Spin("Copying Files")
+	copySpinner.Done()
+
+	entries, plans := temp.planExpansion(true)
+
+	if failed := temp.Conflicts.Failed(); len(failed) > 0 {
+		cmdutil.ExitWithMessage(fmt.Sprintf("The following files already exist and differ from the template:\n  /%s", strings.Join(failed, "\n  /")))
+	}
+
+	for i, c := range entries {
+		copyExpansion(temp, c, plans[i])
+	}
 }
 
 // Fill fills the templates specified wtihin the template.
@@ -89,28 +369,66 @@ func (temp Template) Fill() {
 	fillSpinner := util.Spin("Running Templates")
 	fillSpinner.Done()
 
+	temp.fill(func(file string, env WhenEnvironment) {
+		fillExpansion(temp, file, env)
+	})
+}
+
+// fill calls fillFile once for each project file to be filled, with the
+// environment it is filled with.
+func (temp Template) fill(fillFile func(file string, env WhenEnvironment)) {
 	env := WhenEnvironment{
 		Answers:  prompt.Answers[temp.Manifest.Name],
 		Template: temp.Manifest,
 		Env:      util.GetEnvMap(),
 	}
 
+	filled := make(map[string]bool)
+	fill := func(file string) {
+		// Leave project files that were kept during a conflict untouched.
+		if filled[file] || temp.Conflicts.Resolution(file) == ConflictSkip {
+			return
+		}
+
+		filled[file] = true
+
+		// Files copied for a Group item are filled with the item, whether
+		// they are named by a Fill entry or marked as templated.
+		fileEnv := env
+		if item, ok := temp.Copied.Items[file]; ok {
+			fileEnv.Item, fileEnv.Index = item.Item, item.Index
+		}
+
+		fillFile(file, fileEnv)
+	}
+
 	for _, f := range temp.Manifest.Fill {
 		env.Vars = f.Vars
 
-		if when.ImplicitlyTrue(f.When) {
-			fillExpansion(temp, f, env)
-
+		if !when.ImplicitlyTrue(f.When) && !when.True(f.When, env) {
 			continue
 		}
 
-		if when.True(f.When, env) {
-			fillExpansion(temp, f, env)
+		for _, file := range temp.fillFiles(f, env) {
+			fill(file)
 		}
 	}
+
+	// Fill every file copied by a Copy entry marked as templated.
+	env.Vars = nil
+	for _, file := range temp.Copied.Templated {
+		fill(file)
+	}
+}
+
+// Prompt runs the manifest Prompt, asking only the questions that
+// were not provided.
+func (temp Template) Prompt(provided prompt.AnswerMap) prompt.AnswerMap {
+	return temp.Manifest.Prompt.AskWith(provided)
 }
 
-// Prompt runs the manifest Prompt.
-func (temp Template) Prompt() prompt.AnswerMap {
-	return temp.Manifest.Prompt.Ask()
+// Answer answers the manifest Prompt from the provided answers and
+// question defaults without asking anything.
+func (temp Template) Answer(provided prompt.AnswerMap) (prompt.AnswerMap, error) {
+	return temp.Manifest.Prompt.Provide(provided)
 }
@@ -0,0 +1,60 @@
+package tmpl
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/lavrahq/cli/packages/fs"
+	"github.com/lavrahq/cli/packages/prompt"
+)
+
+func TestFillItem(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lavra-fill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"named/api.txt":     "{{ .Index }} {{ .Item.Name }}",
+		"templated/api.txt": "{{ .Index }} {{ .Item.Name }}",
+	}
+
+	temp := Template{
+		Directory: fs.Directory{Path: dir},
+		Manifest: TemplateManifest{
+			Name: "fill-item",
+			Fill: []Fill{{File: "named/api.txt"}},
+		},
+		Conflicts: &ConflictLog{},
+		Copied:    &CopyLog{},
+	}
+
+	for file, content := range files {
+		if err := os.MkdirAll(path.Join(dir, path.Dir(file)), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(path.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		temp.Copied.Add(file, dir, file == "templated/api.txt")
+		temp.Copied.AddItem(file, CopyItem{Item: prompt.AnswerMap{"Name": "api"}, Index: 1})
+	}
+
+	temp.Fill()
+
+	for file := range files {
+		data, err := ioutil.ReadFile(path.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != "1 api" {
+			t.Errorf("Fill() filled /%s with %q, want %q", file, data, "1 api")
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package tmpl
+
+import (
+	"regexp"
+	"strings"
+)
+
+// globToRegexp converts a glob into a regular expression. Besides the
+// `*`, `?` and `[...]` of filepath.Match, `**` matches across
+// directories and `{a,b}` matches either alternative.
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+
+	inGroup := false
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+
+		switch {
+		case c == '*' && strings.HasPrefix(glob[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(glob[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end == -1 {
+				expr.WriteString(regexp.QuoteMeta(string(c)))
+
+				continue
+			}
+
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+
+			expr.WriteString("[" + class + "]")
+			i += end
+		case c == '{':
+			inGroup = true
+			expr.WriteString("(?:")
+		case c == '}' && inGroup:
+			inGroup = false
+			expr.WriteString(")")
+		case c == ',' && inGroup:
+			expr.WriteString("|")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	expr.WriteString("$")
+
+	return regexp.Compile(expr.String())
+}
+
+// MatchGlob returns true if the slash separated name matches the glob.
+func MatchGlob(glob string, name string) (bool, error) {
+	expr, err := globToRegexp(strings.TrimPrefix(glob, "/"))
+	if err != nil {
+		return false, err
+	}
+
+	return expr.MatchString(strings.TrimPrefix(name, "/")), nil
+}
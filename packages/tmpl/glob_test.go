@@ -0,0 +1,75 @@
+package tmpl
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		glob  string
+		name  string
+		match bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/sub/main.go", true},
+		{"cmd/**", "cmd/sub/main.go", true},
+		{"cmd/**", "pkg/main.go", false},
+		{"src/**/test", "src/test", true},
+		{"src/**/test", "src/a/b/test", true},
+		{"?.txt", "a.txt", true},
+		{"?.txt", "ab.txt", false},
+		{"?.txt", "/.txt", false},
+		{"[ab].txt", "a.txt", true},
+		{"[ab].txt", "c.txt", false},
+		{"[!ab].txt", "c.txt", true},
+		{"[!ab].txt", "a.txt", false},
+		{"*.{yml,yaml}", "config.yaml", true},
+		{"*.{yml,yaml}", "config.yml", true},
+		{"*.{yml,yaml}", "config.json", false},
+		{"/docs/*.md", "docs/README.md", true},
+		{"docs/*.md", "/docs/README.md", true},
+		{"a+b.txt", "a+b.txt", true},
+		{"a+b.txt", "aab.txt", false},
+		{"[.txt", "[.txt", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.glob+" "+test.name, func(t *testing.T) {
+			match, err := MatchGlob(test.glob, test.name)
+			if err != nil {
+				t.Fatalf("MatchGlob(%q, %q) returned %s", test.glob, test.name, err)
+			}
+
+			if match != test.match {
+				t.Errorf("MatchGlob(%q, %q) = %v, want %v", test.glob, test.name, match, test.match)
+			}
+		})
+	}
+}
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		glob string
+		expr string
+	}{
+		{"*.go", `^[^/]*\.go$`},
+		{"**/*.go", `^(?:.*/)?[^/]*\.go$`},
+		{"a/**", `^a/.*$`},
+		{"?", `^[^/]$`},
+		{"[!a]", `^[^a]$`},
+		{"{a,b}", `^(?:a|b)$`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.glob, func(t *testing.T) {
+			expr, err := globToRegexp(test.glob)
+			if err != nil {
+				t.Fatalf("globToRegexp(%q) returned %s", test.glob, err)
+			}
+
+			if expr.String() != test.expr {
+				t.Errorf("globToRegexp(%q) = %s, want %s", test.glob, expr, test.expr)
+			}
+		})
+	}
+}
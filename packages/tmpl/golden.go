@@ -0,0 +1,136 @@
+package tmpl
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/lavrahq/cli/packages/fs"
+	"github.com/lavrahq/cli/packages/prompt"
+	"github.com/otiai10/copy"
+)
+
+// Template tests are kept within the template directory, one directory
+// per case holding the answers to expand with and the expected output.
+const (
+	TestDirectory    = "tests"
+	TestAnswersFile  = "answers.yml"
+	TestExpectedPath = "expected"
+)
+
+// TestCase is a fixture answer set and the golden directory its
+// expansion is compared against.
+type TestCase struct {
+	Name     string
+	Answers  string
+	Expected fs.Directory
+}
+
+// TestCases lists the template's test cases, in name order.
+func (temp Template) TestCases() ([]TestCase, error) {
+	var cases []TestCase
+
+	root := path.Join(temp.TemplateDirectory.Path, TestDirectory)
+	entries, err := ioutil.ReadDir(root)
+	if os.IsNotExist(err) {
+		return cases, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		answers := path.Join(root, entry.Name(), TestAnswersFile)
+		if _, err := os.Stat(answers); !entry.IsDir() || err != nil {
+			continue
+		}
+
+		expected, _ := fs.MakeDirectory(path.Join(root, entry.Name(), TestExpectedPath))
+		cases = append(cases, TestCase{
+			Name:     entry.Name(),
+			Answers:  answers,
+			Expected: expected,
+		})
+	}
+
+	sort.Slice(cases, func(i, j int) bool { return cases[i].Name < cases[j].Name })
+
+	return cases, nil
+}
+
+// Compare returns how the actual directory differs from the expected
+// one. Files only within actual are created, files that differ are
+// updated and files missing from actual are deleted.
+func Compare(expected, actual fs.Directory) ([]FileChange, error) {
+	var changes []FileChange
+
+	previewed, err := Preview(expected, actual)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range previewed {
+		if change.Action != ChangeUnchanged {
+			changes = append(changes, change)
+		}
+	}
+
+	expectedFiles := make(map[string]bool)
+	if expected.Exists() {
+		if expectedFiles, err = listFiles(expected.Path); err != nil {
+			return nil, err
+		}
+	}
+
+	actualFiles, err := listFiles(actual.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for name := range expectedFiles {
+		if !actualFiles[name] {
+			missing = append(missing, name)
+		}
+	}
+
+	sort.Strings(missing)
+
+	for _, name := range missing {
+		changes = append(changes, FileChange{File: name, Action: ChangeDeleted})
+	}
+
+	return changes, nil
+}
+
+// Test expands the template with the test case's answers into a scratch
+// directory and compares it with the expected output. When update is
+// set the expected output is replaced with the expansion instead.
+func (temp Template) Test(test TestCase, update bool) ([]FileChange, error) {
+	answers, err := prompt.LoadAnswersFile(test.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	scratch, err := ioutil.TempDir("", "lavra-template-test")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(scratch)
+
+	dir, _ := fs.MakeDirectory(scratch)
+	if _, err := temp.Expand(dir, answers); err != nil {
+		return nil, err
+	}
+
+	if update {
+		if err := os.RemoveAll(test.Expected.Path); err != nil {
+			return nil, err
+		}
+
+		return nil, copy.Copy(dir.Path, test.Expected.Path)
+	}
+
+	return Compare(test.Expected, dir)
+}
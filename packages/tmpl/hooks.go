@@ -0,0 +1,264 @@
+package tmpl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/lavrahq/cli/packages/prompt"
+	"github.com/lavrahq/cli/packages/when"
+	"github.com/lavrahq/cli/util"
+	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/logrusorgru/aurora"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// Hook stages, run around the copy and fill steps of an expansion.
+const (
+	HookPreCopy  = "preCopy"
+	HookPostCopy = "postCopy"
+	HookPreFill  = "preFill"
+	HookPostFill = "postFill"
+)
+
+// Hook is a command ran during an expansion, such as `git init` or
+// `npm install`.
+type Hook struct {
+	Name string            `yaml:"name"`
+	Run  string            `yaml:"run"`
+	When string            `yaml:"when"`
+	Dir  string            `yaml:"dir"`
+	Env  map[string]string `yaml:"env"`
+}
+
+// Hooks holds the hooks for each stage of an expansion.
+type Hooks struct {
+	PreCopy  []Hook `yaml:"preCopy"`
+	PostCopy []Hook `yaml:"postCopy"`
+	PreFill  []Hook `yaml:"preFill"`
+	PostFill []Hook `yaml:"postFill"`
+}
+
+// Stage returns the hooks for the given stage.
+func (hooks Hooks) Stage(stage string) []Hook {
+	switch stage {
+	case HookPreCopy:
+		return hooks.PreCopy
+	case HookPostCopy:
+		return hooks.PostCopy
+	case HookPreFill:
+		return hooks.PreFill
+	case HookPostFill:
+		return hooks.PostFill
+	}
+
+	return nil
+}
+
+// All returns the hooks of every stage, in the order they run.
+func (hooks Hooks) All() []Hook {
+	var all []Hook
+
+	for _, stage := range []string{HookPreCopy, HookPostCopy, HookPreFill, HookPostFill} {
+		all = append(all, hooks.Stage(stage)...)
+	}
+
+	return all
+}
+
+// answerEnvValue converts an answer into an environment variable value,
+// given in its plain form. Lists are joined with commas, and Group
+// answers are encoded as a JSON list of objects.
+func answerEnvValue(question prompt.Question, value interface{}) string {
+	switch v := question.Plain(value).(type) {
+	case []string:
+		return strings.Join(v, ",")
+	case []prompt.AnswerMap:
+		encoded, _ := json.Marshal(v)
+
+		return string(encoded)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// AnswerEnv returns the answers as `LAVRA_<NAME>` environment variables.
+func (temp Template) AnswerEnv(answers prompt.AnswerMap) []string {
+	questions := make(map[string]prompt.Question)
+	for _, question := range temp.Manifest.Prompt.Questions {
+		questions[question.Name] = question
+	}
+
+	var env []string
+	for name, value := range answers {
+		if strings.HasPrefix(name, "Raw") {
+			continue
+		}
+
+		env = append(env, fmt.Sprintf("%s=%s", prompt.EnvName(name), answerEnvValue(questions[name], value)))
+	}
+
+	sort.Strings(env)
+
+	return env
+}
+
+// hooksDigest returns a digest of every hook the template runs, which
+// includes the hooks of the templates it extends and includes.
+func (temp Template) hooksDigest() string {
+	data, _ := yaml.Marshal(temp.Manifest.Hooks)
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// trustKey returns the key the user's trust in the template's hooks is
+// remembered by. It includes the digest of the hooks, so that trust is
+// asked for again whenever any of them changes.
+func (temp Template) trustKey() string {
+	return temp.From + "#" + temp.hooksDigest()
+}
+
+// IsTrusted returns true if the user has trusted the template's hooks
+// as they are now.
+func (temp Template) IsTrusted() bool {
+	for _, trusted := range viper.GetStringSlice("templates.trusted") {
+		if trusted == temp.trustKey() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Trust remembers that the user trusts the template's hooks, replacing
+// the trust given to earlier versions of them.
+func (temp Template) Trust() {
+	trusted := []string{}
+	for _, key := range viper.GetStringSlice("templates.trusted") {
+		if key != temp.From && !strings.HasPrefix(key, temp.From+"#") {
+			trusted = append(trusted, key)
+		}
+	}
+
+	viper.Set("templates.trusted", append(trusted, temp.trustKey()))
+	viper.WriteConfig()
+}
+
+// EnsureHooksTrusted makes sure the user trusts the template before its
+// hooks run, asking for confirmation unless trust is given. Hooks are
+// asked about again whenever they change, including those inherited
+// from the templates it extends and includes. Runs that never ask
+// anything need trust to be given instead.
+func (temp Template) EnsureHooksTrusted(trust bool) {
+	hooks := temp.Manifest.Hooks.All()
+	if len(hooks) == 0 || trust || temp.IsTrusted() {
+		return
+	}
+
+	if temp.NoInput || !util.IsInteractive() {
+		cmdutil.ExitWithMessage(fmt.Sprintf("The `%s` template runs hooks and has not been trusted, use --trust to allow them.", temp.From))
+	}
+
+	fmt.Println()
+	fmt.Printf(" %s\n\n", aurora.Yellow(fmt.Sprintf("The `%s` template wants to run the following commands:", temp.From)))
+	for _, hook := range hooks {
+		fmt.Printf("   $ %s\n", hook.Run)
+	}
+	fmt.Println()
+
+	trusted := false
+	err := survey.AskOne(&survey.Confirm{
+		Message: "Do you trust this template and want to run its hooks?",
+	}, &trusted)
+	cmdutil.CheckCommandError(err, "confirming template hooks")
+
+	if !trusted {
+		cmdutil.ExitWithMessage("The template's hooks were not trusted.")
+	}
+
+	temp.Trust()
+}
+
+// runHook runs a single hook within the project directory, streaming
+// its output.
+func runHook(temp Template, hook Hook, env WhenEnvironment) {
+	name := hook.Name
+	if name == "" {
+		name = hook.Run
+	}
+
+	dir, err := renderString(temp, hook.Dir, env)
+	cmdutil.CheckCommandError(err, fmt.Sprintf("rendering hook dir, %s", name))
+
+	dir = path.Join(temp.Directory.Path, dir)
+	if !withinDir(temp.Directory.Path, dir) {
+		cmdutil.ExitWithMessage(fmt.Sprintf("The dir of hook %s is outside of the project.", name))
+	}
+
+	err = os.MkdirAll(dir, os.ModePerm)
+	cmdutil.CheckCommandError(err, fmt.Sprintf("creating hook dir, %s", name))
+
+	command := exec.Command("sh", "-c", hook.Run)
+	if runtime.GOOS == "windows" {
+		command = exec.Command("cmd", "/C", hook.Run)
+	}
+
+	command.Dir = dir
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	command.Env = append(os.Environ(), temp.AnswerEnv(env.Answers)...)
+
+	for key, value := range hook.Env {
+		rendered, err := renderString(temp, value, env)
+		cmdutil.CheckCommandError(err, fmt.Sprintf("rendering hook env %s, %s", key, name))
+
+		command.Env = append(command.Env, key+"="+rendered)
+	}
+
+	fmt.Printf(" + Running %s\n", name)
+
+	err = command.Run()
+	cmdutil.CheckCommandError(err, fmt.Sprintf("running hook, %s", name))
+}
+
+// RunHooks runs the template's hooks for the given stage.
+func (temp Template) RunHooks(stage string) {
+	hooks := temp.Manifest.Hooks.Stage(stage)
+	if len(hooks) == 0 {
+		return
+	}
+
+	hookSpinner := util.Spin(fmt.Sprintf("Running %s hooks", stage))
+	hookSpinner.Done()
+
+	env := WhenEnvironment{
+		Answers:  prompt.Answers[temp.Manifest.Name],
+		Template: temp.Manifest,
+		Env:      util.GetEnvMap(),
+	}
+
+	for _, hook := range hooks {
+		if when.ImplicitlyTrue(hook.When) {
+			runHook(temp, hook, env)
+
+			continue
+		}
+
+		if when.True(hook.When, env) {
+			runHook(temp, hook, env)
+		}
+	}
+}
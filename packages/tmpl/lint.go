@@ -0,0 +1,286 @@
+package tmpl
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/lavrahq/cli/packages/prompt"
+	"github.com/lavrahq/cli/packages/when"
+)
+
+// LintIssue is a problem found within a template manifest, along with
+// the manifest field it was found in.
+type LintIssue struct {
+	Field   string
+	Message string
+}
+
+// String formats the issue for display.
+func (issue LintIssue) String() string {
+	return fmt.Sprintf("%s: %s", issue.Field, issue.Message)
+}
+
+// isTemplated returns true if the path contains template actions, and
+// so can only be known once the template is expanded.
+func isTemplated(p string) bool {
+	return strings.Contains(p, "{{")
+}
+
+// copiedFiles lists the project files the manifest's Copy entries
+// write, as written within the template. The second value is false if
+// an entry copies from a templated path, so the list is incomplete.
+func (temp Template) copiedFiles() (map[string]bool, bool) {
+	files := make(map[string]bool)
+	complete := true
+
+	for _, c := range temp.Manifest.Copy {
+		if isTemplated(c.From) {
+			complete = false
+
+			continue
+		}
+
+		source := temp.copySource(c, c.From)
+		filepath.Walk(source, func(file string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+
+			rel, _ := filepath.Rel(source, file)
+			files[cleanFile(path.Join(c.Into, filepath.ToSlash(rel)))] = true
+
+			return nil
+		})
+	}
+
+	return files, complete
+}
+
+// isGroup returns true if the named question is a Group question.
+func isGroup(questions []prompt.Question, name string) bool {
+	for _, question := range questions {
+		if question.Name == name {
+			return question.Type == "Group"
+		}
+	}
+
+	return false
+}
+
+// Lint checks the manifest for unknown question types and transforms,
+// `when` expressions that do not compile, copies from paths missing
+// from the template, and fills of files that are never copied.
+func (temp Template) Lint() []LintIssue {
+	var issues []LintIssue
+	add := func(field string, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	checkWhen := func(field string, program string, env interface{}) {
+		if err := when.Compile(program, env); err != nil {
+			add(field, "the expression does not compile, %s", strings.TrimSpace(err.Error()))
+		}
+	}
+
+	manifest := temp.Manifest
+	if manifest.Name == "" {
+		add("name", "the template has no name")
+	}
+
+	var lintQuestions func(prefix string, questions []prompt.Question, names map[string]bool)
+	lintQuestions = func(prefix string, questions []prompt.Question, names map[string]bool) {
+		for i, question := range questions {
+			field := fmt.Sprintf("%s[%d]", prefix, i)
+
+			switch {
+			case question.Name == "":
+				add(field+".name", "the question has no name")
+			case names[question.Name]:
+				add(field+".name", "the question `%s` is asked more than once", question.Name)
+			}
+			names[question.Name] = true
+
+			if !prompt.IsValidPromptType(question.Type) {
+				add(field+".type", "`%s` is not a valid question type", question.Type)
+			}
+
+			for j, transform := range question.Transform {
+				name, program := prompt.ParseTransform(transform)
+
+				switch {
+				case !prompt.IsValidTransformerType(transform):
+					add(fmt.Sprintf("%s.transform[%d]", field, j), "`%s` is not a valid transform", transform)
+				case name == "expr":
+					checkWhen(fmt.Sprintf("%s.transform[%d]", field, j), program, prompt.TransformEnvironment{})
+				}
+			}
+
+			if (question.Type == "Select" || question.Type == "MultiSelect") && len(question.Options.Options) == 0 {
+				add(field+".prompt.options", "the %s question has no options", question.Type)
+			}
+
+			if !prompt.IsValidPathType(question.Options.PathType) {
+				add(field+".prompt.pathType", "`%s` is not a valid path type", question.Options.PathType)
+			}
+
+			if question.Validate.Min != nil && question.Validate.Max != nil && *question.Validate.Min > *question.Validate.Max {
+				add(field+".validate", "min is greater than max")
+			}
+
+			if question.Validate.Pattern != "" {
+				if _, err := regexp.Compile(question.Validate.Pattern); err != nil {
+					add(field+".validate.pattern", "the pattern is invalid, %s", err)
+				}
+			}
+
+			if (question.Validate.MinItems > 0 || question.Validate.MaxItems > 0) && question.Type != "MultiSelect" && question.Type != "Group" {
+				add(field+".validate", "minItems and maxItems only apply to MultiSelect and Group questions")
+			}
+
+			for validator := range question.Validate.Messages {
+				if !prompt.IsValidValidatorType(validator) {
+					add(field+".validate.messages", "`%s` is not a validator", validator)
+				}
+			}
+
+			checkWhen(field+".validate.custom", question.Validate.Custom, prompt.ValidationEnvironment{})
+
+			if prompt.IsTemplated(question.Options.Default) {
+				if _, err := prompt.ParseText(question.Options.Default); err != nil {
+					add(field+".prompt.default", "the default does not parse, %s", err)
+				}
+			} else if prompt.IsTypedPromptType(question.Type) {
+				if _, err := question.Coerce(question.Options.Default); err != nil {
+					add(field+".prompt.default", "the default `%s` is invalid: %s", question.Options.Default, err)
+				}
+			}
+
+			checkWhen(field+".when", question.When, prompt.WhenEnvironment{})
+
+			if question.Type == "Group" {
+				if len(question.Questions) == 0 {
+					add(field+".questions", "the Group question has no questions")
+				}
+
+				switch {
+				case !prompt.IsValidRepeat(question.Repeat):
+					add(field+".repeat", "`%s` is not a valid repeat mode", question.Repeat)
+				case question.Repeat == prompt.RepeatCount && question.Count == "":
+					add(field+".count", "the Group question repeats by count, but has no count")
+				case question.Repeat == prompt.RepeatCount:
+					checkWhen(field+".count", question.Count, prompt.WhenEnvironment{})
+				}
+
+				lintQuestions(field+".questions", question.Questions, make(map[string]bool))
+			}
+		}
+	}
+
+	names := make(map[string]bool)
+	lintQuestions("prompt.questions", manifest.Prompt.Questions, names)
+
+	for i, c := range manifest.Prompt.Computed {
+		field := fmt.Sprintf("prompt.computed[%d]", i)
+
+		switch {
+		case c.Name == "":
+			add(field+".name", "the computed answer has no name")
+		case names[c.Name]:
+			add(field+".name", "`%s` is already answered by a question or computed answer", c.Name)
+		}
+		names[c.Name] = true
+
+		switch {
+		case c.Expr == "" && c.Template == "":
+			add(field, "the computed answer has neither an expr nor a template")
+		case c.Expr != "" && c.Template != "":
+			add(field, "the computed answer has both an expr and a template")
+		case c.Expr != "":
+			checkWhen(field+".expr", c.Expr, prompt.WhenEnvironment{})
+		default:
+			if _, err := prompt.ParseText(c.Template); err != nil {
+				add(field+".template", "the template does not parse, %s", err)
+			}
+		}
+
+		checkWhen(field+".when", c.When, prompt.WhenEnvironment{})
+	}
+
+	for i, c := range manifest.Copy {
+		field := fmt.Sprintf("copy[%d]", i)
+
+		if c.Each != "" && !isGroup(manifest.Prompt.Questions, c.Each) {
+			add(field+".each", "`%s` is not a Group question", c.Each)
+		}
+
+		if c.Conflict != "" && !IsValidConflictPolicy(c.Conflict) {
+			add(field+".conflict", "`%s` is not a valid conflict policy", c.Conflict)
+		}
+
+		if !isTemplated(c.From) {
+			switch _, err := os.Stat(temp.copySource(c, c.From)); {
+			case !withinDir(temp.copyRoot(c), temp.copySource(c, c.From)):
+				add(field+".from", "/%s is outside of the template directory", c.From)
+			case err != nil:
+				add(field+".from", "/%s does not exist within the template directory", c.From)
+			}
+		}
+
+		if !isTemplated(c.Into) && strings.HasPrefix(cleanFile(c.Into), "..") {
+			add(field+".into", "/%s is outside of the project directory", c.Into)
+		}
+
+		checkWhen(field+".when", c.When, WhenEnvironment{})
+	}
+
+	copied, complete := temp.copiedFiles()
+	for i, f := range manifest.Fill {
+		field := fmt.Sprintf("fill[%d]", i)
+
+		switch {
+		case f.File == "" && f.Glob == "":
+			add(field, "the fill has neither a file nor a glob")
+		case f.File != "" && f.Glob != "":
+			add(field, "the fill has both a file and a glob")
+		case f.Glob != "":
+			if _, err := MatchGlob(f.Glob, ""); err != nil {
+				add(field+".glob", "the glob is invalid, %s", err)
+			}
+		case complete && !copied[cleanFile(f.File)]:
+			add(field+".file", "/%s is not copied into the project by any copy entry", cleanFile(f.File))
+		}
+
+		checkWhen(field+".when", f.When, WhenEnvironment{})
+	}
+
+	for _, stage := range []string{HookPreCopy, HookPostCopy, HookPreFill, HookPostFill} {
+		for i, hook := range manifest.Hooks.Stage(stage) {
+			field := fmt.Sprintf("hooks.%s[%d]", stage, i)
+
+			if hook.Run == "" {
+				add(field+".run", "the hook has no command to run")
+			}
+
+			checkWhen(field+".when", hook.When, WhenEnvironment{})
+		}
+	}
+
+	for i, check := range manifest.Checks {
+		if strings.TrimSpace(check) == "" {
+			add(fmt.Sprintf("checks[%d]", i), "the check has no command")
+		}
+	}
+
+	if manifest.Notes != "" {
+		if _, err := template.New("notes").Funcs(temp.FuncMap()).Parse(manifest.Notes); err != nil {
+			add("notes", "the notes do not parse, %s", err)
+		}
+	}
+
+	return issues
+}
@@ -3,9 +3,10 @@ package tmpl
 import (
 	"crypto/md5"
 	"encoding/hex"
-	"io/ioutil"
+	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -13,47 +14,78 @@ import (
 	"github.com/lavrahq/cli/packages/prompt"
 	"github.com/lavrahq/cli/util"
 	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/logrusorgru/aurora"
+	"github.com/mitchellh/go-homedir"
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
 	"gopkg.in/src-d/go-git.v4/storage/memory"
-	"gopkg.in/yaml.v2"
 )
 
 // Copy is an instance of each expansion configuration
 // object. Expansions are used to determine the files/directories to
 // expand (copy) form the template into the project.
 type Copy struct {
-	Into string `yaml:"into"`
-	From string `yaml:"from"`
-	When string `yaml:"when"`
+	Into     string `yaml:"into"`
+	From     string `yaml:"from"`
+	When     string `yaml:"when"`
+	Conflict string `yaml:"conflict"`
+	Fill     bool   `yaml:"fill"`
+	Each     string `yaml:"each"`
+	Source   string `yaml:"-"`
 }
 
 // Fill is an instance of each template fill configuration
 // object. Templates are used to specify templates the files that
 // should be ran through the template engine for variable replacement.
+// Either a single File, or a Glob matched against the copied files,
+// may be given.
 type Fill struct {
-	File string                 `yaml:"file"`
-	When string                 `yaml:"when"`
-	Vars map[string]interface{} `yaml:"vars"`
+	File    string                 `yaml:"file"`
+	Glob    string                 `yaml:"glob"`
+	Exclude []string               `yaml:"exclude"`
+	When    string                 `yaml:"when"`
+	Vars    map[string]interface{} `yaml:"vars"`
 }
 
 // TemplateManifest is an instance of the template configuration
-// file.
+// file. A manifest may extend a base template and include add-on
+// templates, which are merged into it when it is loaded.
 type TemplateManifest struct {
-	Name        string        `yaml:"name"`
-	Author      string        `yaml:"author"`
-	Description string        `yaml:"description"`
-	Prompt      prompt.Prompt `yaml:"prompt"`
-	Copy        []Copy        `yaml:"copy"`
-	Fill        []Fill        `yaml:"fill"`
+	APIVersion      string        `yaml:"apiVersion"`
+	MinCLIVersion   string        `yaml:"minCliVersion"`
+	MinFuncsVersion int           `yaml:"minFuncsVersion"`
+	Name            string        `yaml:"name"`
+	Author          string        `yaml:"author"`
+	Description     string        `yaml:"description"`
+	Extends         string        `yaml:"extends"`
+	Include         []string      `yaml:"include"`
+	Prompt          prompt.Prompt `yaml:"prompt"`
+	Copy            []Copy        `yaml:"copy"`
+	Fill            []Fill        `yaml:"fill"`
+	Hooks           Hooks         `yaml:"hooks"`
+	Checks          []string      `yaml:"checks"`
+	Notes           string        `yaml:"notes"`
 }
 
 // Template holds information related to the template being
 // rendered.
 type Template struct {
 	From              string
+	Ref               string
+	Local             bool
+	Archive           bool
+	Revision          string
+	Remote            string
 	Directory         fs.Directory
 	TemplateDirectory fs.Directory
 	Manifest          TemplateManifest
+	Parts             []RecordPart
+	Pins              map[string]string
+	Refresh           bool
+	ConflictPolicy    string
+	NoInput           bool
+	Conflicts         *ConflictLog
+	Copied            *CopyLog
 }
 
 // getCountOfSlashesInRemote returns the number of forward
@@ -65,106 +97,276 @@ func getCountOfSlashesInRemote(remote string) int {
 	return len(slashes)
 }
 
+// LocalTemplatePath returns the path of a template given as a path on
+// disk, such as `./path/to/template` or `file:///path/to/template`.
+func LocalTemplatePath(template string) (string, bool) {
+	if strings.HasPrefix(template, "file://") {
+		return strings.TrimPrefix(template, "file://"), true
+	}
+
+	if strings.HasPrefix(template, ".") || strings.HasPrefix(template, "~") || filepath.IsAbs(template) {
+		dir, _ := homedir.Expand(template)
+
+		return dir, true
+	}
+
+	return "", false
+}
+
 // Make initializes a Template given a dir and the template name
-// or remote.
+// or remote, optionally pinned to a tag, branch, commit or semver
+// range with `@`. Templates given as a path on disk, or found by name
+// within the local templates path, are used as-is without git, and
+// template archives are unpacked into the cache.
 func Make(expandDir fs.Directory, template string) Template {
 	var templateDir fs.Directory
+	from, ref := SplitRef(template)
 	templateConfig := Template{
-		From:      template,
+		From:      from,
+		Ref:       ref,
 		Directory: expandDir,
+		Conflicts: &ConflictLog{},
+		Copied:    &CopyLog{},
 	}
 
-	if templateConfig.IsTemplateAvailableLocally() {
-		templateDir, _ = fs.MakeDirectory(path.Join(GetLocalPath(), template))
+	if IsArchive(from) {
+		if localPath, ok := LocalTemplatePath(from); ok {
+			templateConfig.From, _ = filepath.Abs(localPath)
+		}
+
+		templateConfig.Ref = ""
+		templateConfig.Archive = true
+		templateDir, _ = fs.MakeDirectory(path.Join(GetCachePath(), templateConfig.GetLocalPathByRemote()))
+		templateConfig.TemplateDirectory = templateDir
+
+		if IsOffline() && isRemoteArchive(templateConfig.From) && !templateDir.Exists() {
+			cmdutil.ExitWithMessage(fmt.Sprintf("The `%s` template archive is not cached and cannot be fetched while offline.", templateConfig.From))
+		}
+
+		return templateConfig
 	}
 
-	safeRemote := templateConfig.GetSafeRemote()
-	if !templateConfig.IsTemplateAvailableRemotely(safeRemote) {
-		cmdutil.ExitWithMessage("The remote template provided is not available.")
+	if localPath, ok := LocalTemplatePath(template); ok {
+		templateDir, err := fs.MakeDirectory(localPath)
+		if err != nil || !templateDir.IsTemplate() {
+			cmdutil.ExitWithMessage("The local template provided does not contain a template manifest.")
+		}
+
+		templateConfig.From = templateDir.Path
+		templateConfig.Ref = ""
+		templateConfig.Local = true
+		templateConfig.TemplateDirectory = templateDir
+
+		return templateConfig
+	}
+
+	// Named local templates shadow remote ones, unless a ref is pinned.
+	if ref == "" && templateConfig.IsTemplateAvailableLocally() {
+		templateDir, _ = fs.MakeDirectory(path.Join(GetLocalPath(), from))
+		templateConfig.Local = true
+		templateConfig.TemplateDirectory = templateDir
+
+		return templateConfig
+	}
+
+	// The remote is resolved once, as registries are read over the network.
+	// A ref the registry entry gives applies unless another is pinned.
+	remote, entryRef := templateConfig.resolveRemote()
+	templateConfig.Remote = remote
+	if templateConfig.Ref == "" {
+		templateConfig.Ref = entryRef
 	}
 
 	templateDir, _ = fs.MakeDirectory(path.Join(GetCachePath(), templateConfig.GetLocalPathByRemote()))
 	templateConfig.TemplateDirectory = templateDir
 
+	// Offline runs and recently fetched caches skip the remote check.
+	if IsOffline() {
+		if !templateDir.Exists() {
+			cmdutil.ExitWithMessage(fmt.Sprintf("The `%s` template is not cached and cannot be fetched while offline.", templateConfig.Source()))
+		}
+
+		return templateConfig
+	}
+
+	if templateConfig.IsCacheFresh() {
+		return templateConfig
+	}
+
+	safeRemote := templateConfig.GetSafeRemote()
+	err := templateConfig.CheckTemplateAvailableRemotely(safeRemote)
+	cmdutil.CheckCommandError(err, "reaching remote template "+safeRemote)
+
 	return templateConfig
 }
 
+// matchSCPRemote matches scp-like Git remotes, such as
+// `git@github.com:org/repo.git`.
+var matchSCPRemote = regexp.MustCompile(`^[A-Za-z0-9._-]+@[A-Za-z0-9.-]+:`)
+
+// isGitURL checks if the remote is already a full Git URL, given with a
+// scheme such as `https://` or in the scp-like `user@host:path` form.
+func isGitURL(remote string) bool {
+	return strings.Contains(remote, "://") || matchSCPRemote.MatchString(remote)
+}
+
 // CheckIfCoreRemote checks if the remote provided is a Lavra
 // repository remote.
 func (temp Template) CheckIfCoreRemote() bool {
-	return getCountOfSlashesInRemote(temp.From) == 0
+	return !isGitURL(temp.From) && getCountOfSlashesInRemote(temp.From) == 0
 }
 
 // CheckIfGithubRemote checks if the remoote provided is a Github
 // repository remote.
 func (temp Template) CheckIfGithubRemote() bool {
-	return getCountOfSlashesInRemote(temp.From) == 1
+	return !isGitURL(temp.From) && getCountOfSlashesInRemote(temp.From) == 1
 }
 
 // IsTemplateAvailableLocally checks whether or not a local template
 // exists by the specific name.
 func (temp Template) IsTemplateAvailableLocally() bool {
-	tm := path.Join(GetLocalPath(), temp.From, "template.json")
-	_, err := os.Stat(tm)
+	dir, err := fs.MakeDirectory(path.Join(GetLocalPath(), temp.From))
 
-	return !os.IsNotExist(err)
+	return err == nil && dir.IsTemplate()
 }
 
-// GetSafeRemote returns a string with the full Git URL for Lavra-owned
-// projects as well Github-hosted projects, and finally the given remote
-// string.
-func (temp Template) GetSafeRemote() string {
+// resolveRemote returns the full Git URL for templates named within a
+// registry, Lavra-owned projects as well Github-hosted projects, and
+// finally the given remote string, along with the ref a registry entry
+// pins the template to. Registries that cannot be read are warned
+// about, and the name falls back to the Lavra-owned project.
+func (temp Template) resolveRemote() (string, string) {
 	if temp.CheckIfCoreRemote() {
+		entry, ok, err := FindRegistryEntry(temp.From)
+		if err != nil {
+			fmt.Printf(" %s\n", aurora.Yellow(fmt.Sprintf("Unable to look up `%s` within the template registries: %s", temp.From, err)))
+		}
+
+		from, ref := SplitRef(entry.Source)
+		if ok && getCountOfSlashesInRemote(from) > 0 {
+			remote, _ := Template{From: from}.resolveRemote()
+
+			return remote, ref
+		}
+
 		s := []string{"https://github.com/lavrahq/cli-project-template-", temp.From, ".git"}
 
-		return strings.Join(s, "")
+		return strings.Join(s, ""), ""
 	}
 
 	if temp.CheckIfGithubRemote() {
 		s := []string{"https://github.com/", temp.From, ".git"}
 
-		return strings.Join(s, "")
+		return strings.Join(s, ""), ""
+	}
+
+	return temp.From, ""
+}
+
+// GetSafeRemote returns the full Git URL of the template, as resolved
+// when the template was made.
+func (temp Template) GetSafeRemote() string {
+	if temp.Remote != "" {
+		return temp.Remote
+	}
+
+	remote, _ := temp.resolveRemote()
+
+	return remote
+}
+
+// Source returns the template as given, including the pinned ref.
+func (temp Template) Source() string {
+	if temp.Ref == "" {
+		return temp.From
 	}
 
-	return temp.From
+	return temp.From + "@" + temp.Ref
 }
 
 // GetLocalPathByRemote returns the local path of the remote provided.
+// Each pinned ref is kept in a separate checkout.
 func (temp Template) GetLocalPathByRemote() string {
 	h := md5.New()
-	h.Write([]byte(temp.From))
+	h.Write([]byte(temp.Source()))
 
 	return hex.EncodeToString(h.Sum(nil))
 }
 
-// LoadRemoteTemplateIntoMemory loads the remote template into memory
-// for
-func (temp Template) LoadRemoteTemplateIntoMemory() (*git.Repository, error) {
-	return git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
-		URL:        temp.GetSafeRemote(),
-		RemoteName: "origin",
-	})
-}
+// CheckTemplateAvailableRemotely checks that the remote template is
+// available, returning why it is not, such as failed authentication.
+// Only the remote's references are listed, rather than cloning the
+// template.
+func (temp Template) CheckTemplateAvailableRemotely(remote string) error {
+	auth, err := RemoteAuth(remote)
+	if err != nil {
+		return err
+	}
 
-// IsTemplateAvailableRemotely returns a boolean stating whether the
-// the remote template is available.
-func (temp Template) IsTemplateAvailableRemotely(remote string) bool {
-	_, err := temp.LoadRemoteTemplateIntoMemory()
+	_, err = git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{remote},
+	}).List(&git.ListOptions{Auth: auth})
 
-	return err == nil
+	return err
 }
 
 // EnsureTemplateIsFetched fetches the remote template, ensuring that the
-// fetched version is the latest.
+// fetched version is the latest, or the version matching the pinned ref.
+// Offline runs and recently fetched caches use the cache as it is,
+// unless the template is being refreshed.
 func (temp Template) EnsureTemplateIsFetched() {
+	if temp.Local {
+		util.Spin("Using local template " + temp.TemplateDirectory.Path).Done()
+
+		return
+	}
+
+	// Archives on disk are unpacked again, in case they were replaced.
+	if temp.Archive && !isRemoteArchive(temp.From) {
+		temp.FetchArchive()
+
+		return
+	}
+
+	if IsOffline() || (temp.IsCacheFresh() && !temp.Refresh) {
+		util.Spin("Using cached template").Done()
+		temp.restoreCache()
+
+		return
+	}
+
+	if temp.Archive {
+		temp.FetchArchive()
+
+		return
+	}
+
+	temp.FetchTemplate()
+}
+
+// FetchTemplate clones or updates the cached template from its remote,
+// regardless of how recently it was fetched.
+func (temp Template) FetchTemplate() {
+	defer temp.markFetched()
+
 	spin := util.Spin("Fetching template")
 	storePath := temp.TemplateDirectory.Path
 
+	auth, err := temp.Auth()
+	cmdutil.CheckCommandError(err, "configuring template auth")
+
 	if _, err := os.Stat(storePath); os.IsNotExist(err) {
-		git.PlainClone(storePath, false, &git.CloneOptions{
+		_, err := git.PlainClone(storePath, false, &git.CloneOptions{
 			URL:               temp.GetSafeRemote(),
 			RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
+			Auth:              auth,
 		})
+		cmdutil.CheckCommandError(err, "cloning template repo")
+
+		if temp.Ref != "" {
+			temp.checkoutRef()
+		}
 
 		spin.Done()
 
@@ -174,11 +376,29 @@ func (temp Template) EnsureTemplateIsFetched() {
 	repo, err := git.PlainOpen(storePath)
 	cmdutil.CheckCommandError(err, "opening template repo")
 
+	if temp.Ref != "" {
+		err = repo.Fetch(&git.FetchOptions{
+			RemoteName: "origin",
+			Tags:       git.AllTags,
+			Force:      true,
+			Auth:       auth,
+		})
+		if err != nil && err != git.NoErrAlreadyUpToDate {
+			cmdutil.CheckCommandError(err, "fetching template repo")
+		}
+
+		temp.checkoutRef()
+		spin.Done()
+
+		return
+	}
+
 	w, err := repo.Worktree()
 	cmdutil.CheckCommandError(err, "opening template repo worktree")
 
 	err = w.Pull(&git.PullOptions{
 		RemoteName: "origin",
+		Auth:       auth,
 	})
 	if err != nil {
 		if err.Error() == "already up-to-date" {
@@ -193,24 +413,54 @@ func (temp Template) EnsureTemplateIsFetched() {
 	spin.Done()
 }
 
+// checkoutRef checks out the commit the pinned ref resolves to.
+func (temp Template) checkoutRef() {
+	repo, err := git.PlainOpen(temp.TemplateDirectory.Path)
+	cmdutil.CheckCommandError(err, "opening template repo")
+
+	hash, err := ResolveRef(repo, temp.Ref)
+	cmdutil.CheckCommandError(err, fmt.Sprintf("resolving template ref, %s", temp.Ref))
+
+	w, err := repo.Worktree()
+	cmdutil.CheckCommandError(err, "opening template repo worktree")
+
+	err = w.Checkout(&git.CheckoutOptions{
+		Hash:  hash,
+		Force: true,
+	})
+	cmdutil.CheckCommandError(err, fmt.Sprintf("checking out template ref, %s", temp.Ref))
+}
+
 // CachedPath returns the path to the locally cached template.
 func (temp Template) CachedPath() string {
 	return path.Join(GetCachePath(), temp.GetLocalPathByRemote(), "template.yml")
 }
 
-// LoadManifest loads the template.yml into the Manifest of the Template.
+// LoadManifest loads the template manifest into the Manifest of the Template,
+// merging in the templates it extends and includes.
 func (temp Template) LoadManifest() Template {
+	return temp.loadManifest(make(map[string]bool))
+}
+
+// loadManifest loads the manifest, tracking the templates being loaded
+// so that templates extending themselves are caught.
+func (temp Template) loadManifest(loading map[string]bool) Template {
 	progress := util.Spin("Fetching template manifest")
 
-	bytes, err := ioutil.ReadFile(temp.CachedPath())
+	bytes, err := temp.TemplateDirectory.ReadTemplateManifest()
 	cmdutil.CheckCommandError(err, "loading manifest")
 
-	err = yaml.Unmarshal(bytes, &temp.Manifest)
+	manifest, err := DecodeManifest(bytes, path.Ext(temp.TemplateDirectory.TemplatePath()))
 	cmdutil.CheckCommandError(err, "converting manifest")
 
-	temp.Manifest.Prompt.Name = temp.Manifest.Name
+	for i := range manifest.Copy {
+		manifest.Copy[i].Source = temp.TemplateDirectory.Path
+	}
 
 	progress.Done()
 
+	temp.Manifest, temp.Parts = temp.compose(manifest, loading)
+	temp.Manifest.Prompt.Name = temp.Manifest.Name
+
 	return temp
 }
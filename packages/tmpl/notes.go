@@ -0,0 +1,57 @@
+package tmpl
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/lavrahq/cli/packages/prompt"
+	"github.com/lavrahq/cli/util"
+	"github.com/lavrahq/cli/util/cmdutil"
+)
+
+// MissingChecks returns the commands listed within the manifest's
+// `checks` that are not found on the PATH.
+func (temp Template) MissingChecks() []string {
+	var missing []string
+
+	for _, command := range temp.Manifest.Checks {
+		if _, err := exec.LookPath(command); err != nil {
+			missing = append(missing, command)
+		}
+	}
+
+	return missing
+}
+
+// EnsureChecksPass makes sure every command the template needs is on
+// the PATH before anything is expanded.
+func (temp Template) EnsureChecksPass() {
+	if len(temp.Manifest.Checks) == 0 {
+		return
+	}
+
+	spin := util.Spin("Checking required commands")
+
+	if missing := temp.MissingChecks(); len(missing) > 0 {
+		cmdutil.ExitWithMessage(fmt.Sprintf("The template needs the following commands, which were not found on the PATH:\n  %s", strings.Join(missing, "\n  ")))
+	}
+
+	spin.Done()
+}
+
+// RenderNotes renders the manifest's `notes` against the answers, for
+// showing once the expansion is done.
+func (temp Template) RenderNotes() (string, error) {
+	if temp.Manifest.Notes == "" {
+		return "", nil
+	}
+
+	notes, err := renderString(temp, temp.Manifest.Notes, WhenEnvironment{
+		Answers:  prompt.Answers[temp.Manifest.Name],
+		Template: temp.Manifest,
+		Env:      util.GetEnvMap(),
+	})
+
+	return strings.TrimSpace(notes), err
+}
@@ -0,0 +1,194 @@
+package tmpl
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/lavrahq/cli/packages/fs"
+	"github.com/lavrahq/cli/util/cmdutil"
+	"github.com/otiai10/copy"
+)
+
+// Preview compares an expansion rendered into a scratch directory with
+// the project directory, returning what the expansion would do to each
+// file without writing anything.
+func Preview(project, rendered fs.Directory) ([]FileChange, error) {
+	var changes []FileChange
+
+	files, err := listFiles(rendered.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		next, err := readIfExists(path.Join(rendered.Path, name))
+		if err != nil {
+			return nil, err
+		}
+
+		current, err := readIfExists(path.Join(project.Path, name))
+		if err != nil {
+			return nil, err
+		}
+
+		change := FileChange{File: name}
+
+		switch {
+		case current == nil:
+			change.Action = ChangeCreated
+		case bytes.Equal(current, next):
+			change.Action = ChangeUnchanged
+		default:
+			change.Action = ChangeUpdated
+			change.Diff = UnifiedDiff("a/"+name, "b/"+name, string(current), string(next))
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// DryRun previews the expansion without writing to the project. The
+// copy is planned against the template's directory, resolving conflicts
+// as Copy would without asking, and the files are rendered into the
+// scratch directory to be compared with the project. Each conflicting
+// file is reported with its resolution.
+func (temp Template) DryRun(scratch fs.Directory) ([]FileChange, error) {
+	entries, plans := temp.planExpansion(false)
+
+	for i, c := range entries {
+		for _, f := range plans[i] {
+			if temp.Conflicts.Resolution(f.File) == ConflictSkip {
+				continue
+			}
+
+			if err := copy.Copy(f.Source, path.Join(scratch.Path, f.File)); err != nil {
+				return nil, err
+			}
+
+			temp.Copied.Add(f.File, f.Dir, c.Fill)
+			if f.Item != nil {
+				temp.Copied.AddItem(f.File, *f.Item)
+			}
+		}
+	}
+
+	rendered := temp
+	rendered.Directory = scratch
+
+	rendered.fill(func(file string, env WhenEnvironment) {
+		// Project files the template does not copy are filled in place.
+		target := path.Join(scratch.Path, file)
+		if _, err := os.Stat(target); os.IsNotExist(err) {
+			err := copy.Copy(path.Join(temp.Directory.Path, file), target)
+			cmdutil.CheckCommandError(err, fmt.Sprintf("reading /%s", file))
+		}
+
+		fillExpansion(rendered, file, env)
+	})
+
+	changes, err := Preview(temp.Directory, scratch)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int)
+	for i, change := range changes {
+		index[change.File] = i
+	}
+
+	for _, conflict := range temp.Conflicts.Conflicts {
+		if i, ok := index[conflict.File]; ok {
+			changes[i].Resolution = conflict.Resolution
+		} else {
+			index[conflict.File] = len(changes)
+			changes = append(changes, FileChange{File: conflict.File, Action: ChangeKept, Resolution: conflict.Resolution})
+		}
+
+		if conflict.Resolution == ConflictBackup {
+			changes = append(changes, FileChange{File: conflict.File + BackupSuffix, Action: ChangeCreated})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].File < changes[j].File
+	})
+
+	return changes, nil
+}
+
+// treeNode is a directory or file within a FileTree.
+type treeNode struct {
+	name     string
+	action   string
+	children []*treeNode
+}
+
+// child returns the named child node, creating it if needed.
+func (node *treeNode) child(name string) *treeNode {
+	for _, c := range node.children {
+		if c.name == name {
+			return c
+		}
+	}
+
+	c := &treeNode{name: name}
+	node.children = append(node.children, c)
+
+	return c
+}
+
+// write renders the node's children with the given line prefix.
+func (node *treeNode) write(out *strings.Builder, prefix string) {
+	for i, c := range node.children {
+		branch, indent := "├── ", "│   "
+		if i == len(node.children)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		if len(c.children) > 0 {
+			fmt.Fprintf(out, "%s%s%s/\n", prefix, branch, c.name)
+			c.write(out, prefix+indent)
+
+			continue
+		}
+
+		fmt.Fprintf(out, "%s%s%s (%s)\n", prefix, branch, c.name, c.action)
+	}
+}
+
+// FileTree renders the changes as a directory tree rooted at root, with
+// the action for each file.
+func FileTree(root string, changes []FileChange) string {
+	tree := &treeNode{}
+
+	for _, change := range changes {
+		node := tree
+		for _, segment := range strings.Split(change.File, "/") {
+			node = node.child(segment)
+		}
+
+		node.action = change.Action
+		if change.Resolution != "" {
+			node.action = fmt.Sprintf("%s, conflict: %s", change.Action, change.Resolution)
+		}
+	}
+
+	var out strings.Builder
+	out.WriteString(root + "\n")
+	tree.write(&out, "")
+
+	return out.String()
+}
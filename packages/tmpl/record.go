@@ -0,0 +1,257 @@
+package tmpl
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/lavrahq/cli/packages/fs"
+	"github.com/lavrahq/cli/packages/prompt"
+	"github.com/lavrahq/cli/util"
+	"github.com/lavrahq/cli/util/cmdutil"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/filemode"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/yaml.v2"
+)
+
+// RecordDirectory is the directory within a generated project that
+// holds the expansion record.
+const RecordDirectory = ".lavra"
+
+// RecordFile is the name of the expansion record file.
+const RecordFile = "answers.yml"
+
+// Record holds everything needed to replay an expansion of a template
+// into a project.
+type Record struct {
+	From    string           `yaml:"from"`
+	Ref     string           `yaml:"ref,omitempty"`
+	Commit  string           `yaml:"commit,omitempty"`
+	Parts   []RecordPart     `yaml:"parts,omitempty"`
+	Answers prompt.AnswerMap `yaml:"answers"`
+}
+
+// RecordPart is a template extended or included by the recorded
+// template, along with the commit it was expanded from. Local parts
+// have no commit.
+type RecordPart struct {
+	Source string `yaml:"source"`
+	Commit string `yaml:"commit,omitempty"`
+}
+
+// RecordPath returns the path of the expansion record within the
+// project directory.
+func RecordPath(dir fs.Directory) string {
+	return path.Join(dir.Path, RecordDirectory, RecordFile)
+}
+
+// HasRecord returns true if the project directory holds an expansion
+// record.
+func HasRecord(dir fs.Directory) bool {
+	_, err := os.Stat(RecordPath(dir))
+
+	return err == nil
+}
+
+// LoadRecord reads the expansion record from the project directory.
+func LoadRecord(dir fs.Directory) (Record, error) {
+	var record Record
+
+	bytes, err := ioutil.ReadFile(RecordPath(dir))
+	if err != nil {
+		return record, err
+	}
+
+	err = yaml.Unmarshal(bytes, &record)
+
+	return record, err
+}
+
+// Source returns the recorded template, including the pinned ref.
+func (record Record) Source() string {
+	return Template{From: record.From, Ref: record.Ref}.Source()
+}
+
+// Pins returns the commit each recorded part was expanded from, keyed
+// by its source, so that a replay composes the same parts.
+func (record Record) Pins() map[string]string {
+	pins := make(map[string]string)
+	for _, part := range record.Parts {
+		if part.Commit != "" {
+			pins[part.Source] = part.Commit
+		}
+	}
+
+	return pins
+}
+
+// HasParts returns true if the parts were expanded from the same
+// commits as the recorded parts.
+func (record Record) HasParts(parts []RecordPart) bool {
+	if len(parts) != len(record.Parts) {
+		return false
+	}
+
+	for i, part := range parts {
+		if part != record.Parts[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Commit returns the commit the fetched template is checked out at, or
+// the Revision it was exported at. Local templates and archives are
+// used as-is and have no commit.
+func (temp Template) Commit() (string, error) {
+	if temp.Local || temp.Archive {
+		return "", errors.New("local templates and archives are not versioned")
+	}
+
+	if temp.Revision != "" {
+		return temp.Revision, nil
+	}
+
+	repo, err := git.PlainOpen(temp.TemplateDirectory.Path)
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	return head.Hash().String(), nil
+}
+
+// Checkout checks out the fetched template at the given commit.
+func (temp Template) Checkout(commit string) {
+	if temp.Local || temp.Archive {
+		cmdutil.ExitWithMessage("Local templates and archives are used as-is and cannot be checked out at a commit.")
+	}
+
+	spin := util.Spin("Checking out template at " + commit)
+
+	repo, err := git.PlainOpen(temp.TemplateDirectory.Path)
+	cmdutil.CheckCommandError(err, "opening template repo")
+
+	w, err := repo.Worktree()
+	cmdutil.CheckCommandError(err, "opening template repo worktree")
+
+	err = w.Checkout(&git.CheckoutOptions{
+		Hash: plumbing.NewHash(commit),
+	})
+	cmdutil.CheckCommandError(err, "checking out template commit")
+
+	spin.Done()
+}
+
+// exportFile writes a single file of a commit into the directory.
+func exportFile(file *object.File, dir string) error {
+	target := filepath.Join(dir, filepath.FromSlash(file.Name))
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+
+	if file.Mode == filemode.Symlink {
+		link, err := file.Contents()
+		if err != nil {
+			return err
+		}
+
+		return os.Symlink(link, target)
+	}
+
+	mode, err := file.Mode.ToOSFileMode()
+	if err != nil {
+		return err
+	}
+
+	reader, err := file.Reader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, reader)
+
+	return err
+}
+
+// Export writes the fetched template's files at the given commit into
+// the directory, without checking the commit out within the cache.
+func (temp Template) Export(commit string, dir string) error {
+	if temp.Local || temp.Archive {
+		return errors.New("local templates and archives are not versioned")
+	}
+
+	repo, err := git.PlainOpen(temp.TemplateDirectory.Path)
+	if err != nil {
+		return err
+	}
+
+	c, err := repo.CommitObject(plumbing.NewHash(commit))
+	if err != nil {
+		return err
+	}
+
+	files, err := c.Files()
+	if err != nil {
+		return err
+	}
+
+	return files.ForEach(func(file *object.File) error {
+		return exportFile(file, dir)
+	})
+}
+
+// Record builds the expansion record from the answers given to the
+// template prompt.
+func (temp Template) Record() Record {
+	commit, _ := temp.Commit()
+
+	return Record{
+		From:    temp.From,
+		Ref:     temp.Ref,
+		Commit:  commit,
+		Parts:   temp.Parts,
+		Answers: temp.Manifest.Prompt.Recordable(),
+	}
+}
+
+// Write writes the expansion record into the project directory.
+func (record Record) Write(dir fs.Directory) error {
+	bytes, err := yaml.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path.Join(dir.Path, RecordDirectory), os.ModePerm); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(RecordPath(dir), bytes, 0644)
+}
+
+// WriteRecord writes the expansion record into the project directory.
+func (temp Template) WriteRecord() {
+	spin := util.Spin("Recording answers")
+
+	err := temp.Record().Write(temp.Directory)
+	cmdutil.CheckCommandError(err, "writing answers record")
+
+	spin.Done()
+}
@@ -0,0 +1,205 @@
+package tmpl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// matchCommitHash matches full and abbreviated commit hashes.
+var matchCommitHash = regexp.MustCompile("^[0-9a-f]{4,40}$")
+
+// matchPartialVersion matches versions missing the minor or patch
+// segments, such as `1` or `1.2`.
+var matchPartialVersion = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?$`)
+
+// SplitRef splits a template source into the template and the ref it
+// is pinned to with `@`, such as `org/repo@v1.4.0`. The `@` of SSH
+// remotes like `git@github.com:org/repo` is not treated as a ref.
+func SplitRef(source string) (string, string) {
+	at := strings.LastIndex(source, "@")
+	if at == -1 || at < strings.LastIndex(source, "/") || at < strings.LastIndex(source, ":") {
+		return source, ""
+	}
+
+	return source[:at], source[at+1:]
+}
+
+// IsVersionRange returns true if the ref is a semver range, such as
+// `^1.2` or `>=1.0.0 <2.0.0`, rather than a tag, branch or commit.
+func IsVersionRange(ref string) bool {
+	return strings.ContainsAny(ref, "^~<>=*")
+}
+
+// completeVersion pads a partial version, such as `1.2`, into a full
+// semver version.
+func completeVersion(version string) (semver.Version, []string, error) {
+	parts := matchPartialVersion.FindStringSubmatch(version)
+	if parts == nil {
+		// Versions with a pre-release or build, such as `1.2.0-beta`,
+		// give every segment.
+		v, err := semver.ParseTolerant(version)
+		segments := []string{fmt.Sprint(v.Major), fmt.Sprint(v.Minor), fmt.Sprint(v.Patch)}
+
+		return v, segments, err
+	}
+
+	segments := []string{}
+	for _, part := range parts[1:] {
+		if part != "" {
+			segments = append(segments, part)
+		}
+	}
+
+	padded := append(append([]string{}, segments...), "0", "0")
+	v, err := semver.Parse(strings.Join(padded[:3], "."))
+
+	return v, segments, err
+}
+
+// ParseVersionRange parses a semver range. In addition to the ranges
+// understood by blang/semver, the caret (`^1.2`) and tilde (`~1.2`)
+// shorthands and partial versions are supported. Like npm, carets on
+// 0.x versions only allow changes below the first non-zero segment, so
+// `^0.2.1` stays below 0.3.0 and `^0.0.3` stays below 0.0.4.
+func ParseVersionRange(spec string) (semver.Range, error) {
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, "^") || strings.HasPrefix(spec, "~") {
+		lower, segments, err := completeVersion(spec[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		var upper semver.Version
+		switch {
+		case spec[0] == '~' && len(segments) > 1:
+			upper = semver.Version{Major: lower.Major, Minor: lower.Minor + 1}
+		case spec[0] == '^' && lower.Major == 0 && lower.Minor == 0 && len(segments) > 2:
+			upper = semver.Version{Major: 0, Minor: 0, Patch: lower.Patch + 1}
+		case spec[0] == '^' && lower.Major == 0 && len(segments) > 1:
+			upper = semver.Version{Major: 0, Minor: lower.Minor + 1}
+		default:
+			upper = semver.Version{Major: lower.Major + 1}
+		}
+
+		return semver.ParseRange(fmt.Sprintf(">=%s <%s", lower, upper))
+	}
+
+	var parts []string
+	for _, part := range strings.Fields(spec) {
+		operator := strings.TrimRight(part, "0123456789.vx*")
+		if version := strings.TrimPrefix(part, operator); version != "" {
+			if v, _, err := completeVersion(version); err == nil {
+				part = operator + v.String()
+			}
+		}
+
+		parts = append(parts, part)
+	}
+
+	return semver.ParseRange(strings.Join(parts, " "))
+}
+
+// resolveVersionRange returns the newest tag within the repository that
+// satisfies the semver range.
+func resolveVersionRange(repo *git.Repository, spec string) (string, error) {
+	versionRange, err := ParseVersionRange(spec)
+	if err != nil {
+		return "", err
+	}
+
+	tags, err := repo.Tags()
+	if err != nil {
+		return "", err
+	}
+
+	var best *semver.Version
+	var bestTag string
+
+	err = tags.ForEach(func(tag *plumbing.Reference) error {
+		v, err := semver.ParseTolerant(tag.Name().Short())
+		if err != nil || !versionRange(v) {
+			return nil
+		}
+
+		if best == nil || v.GT(*best) {
+			best = &v
+			bestTag = tag.Name().Short()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if best == nil {
+		return "", fmt.Errorf("no tag satisfies the version range `%s`", spec)
+	}
+
+	return bestTag, nil
+}
+
+// resolveAbbreviatedHash finds the commit starting with the abbreviated
+// hash, which must not match more than one commit.
+func resolveAbbreviatedHash(repo *git.Repository, prefix string) (plumbing.Hash, error) {
+	commits, err := repo.CommitObjects()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	found := plumbing.ZeroHash
+	err = commits.ForEach(func(commit *object.Commit) error {
+		if !strings.HasPrefix(commit.Hash.String(), prefix) {
+			return nil
+		}
+
+		if !found.IsZero() && found != commit.Hash {
+			return fmt.Errorf("ambiguous ref `%s` matches more than one commit of the template", prefix)
+		}
+
+		found = commit.Hash
+
+		return nil
+	})
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	if found.IsZero() {
+		return plumbing.ZeroHash, plumbing.ErrReferenceNotFound
+	}
+
+	return found, nil
+}
+
+// ResolveRef resolves a semver range, tag, branch or commit within the
+// repository to the commit it points at.
+func ResolveRef(repo *git.Repository, ref string) (plumbing.Hash, error) {
+	if IsVersionRange(ref) {
+		tag, err := resolveVersionRange(repo, ref)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+
+		ref = tag
+	}
+
+	for _, name := range []string{"refs/tags/" + ref, "refs/remotes/origin/" + ref, ref} {
+		if hash, err := repo.ResolveRevision(plumbing.Revision(name)); err == nil {
+			return *hash, nil
+		}
+	}
+
+	if matchCommitHash.MatchString(ref) {
+		return resolveAbbreviatedHash(repo, ref)
+	}
+
+	return plumbing.ZeroHash, fmt.Errorf("`%s` is not a tag, branch or commit of the template", ref)
+}
@@ -0,0 +1,137 @@
+package tmpl
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blang/semver"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+func TestSplitRef(t *testing.T) {
+	tests := []struct {
+		source string
+		from   string
+		ref    string
+	}{
+		{"react", "react", ""},
+		{"react@v1.2.0", "react", "v1.2.0"},
+		{"org/repo@^1.2", "org/repo", "^1.2"},
+		{"https://github.com/org/repo.git", "https://github.com/org/repo.git", ""},
+		{"https://github.com/org/repo.git@main", "https://github.com/org/repo.git", "main"},
+		{"git@github.com:org/repo.git", "git@github.com:org/repo.git", ""},
+		{"git@github.com:org/repo.git@v2", "git@github.com:org/repo.git", "v2"},
+		{"ssh://git@host/repo", "ssh://git@host/repo", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.source, func(t *testing.T) {
+			from, ref := SplitRef(test.source)
+
+			if from != test.from || ref != test.ref {
+				t.Errorf("SplitRef(%q) = %q, %q, want %q, %q", test.source, from, ref, test.from, test.ref)
+			}
+		})
+	}
+}
+
+func TestParseVersionRange(t *testing.T) {
+	tests := []struct {
+		spec     string
+		accepted []string
+		rejected []string
+	}{
+		{"^1.2", []string{"1.2.0", "1.9.9"}, []string{"1.1.9", "2.0.0"}},
+		{"^1.2.3", []string{"1.2.3", "1.3.0"}, []string{"1.2.2", "2.0.0"}},
+		{"^0.2.1", []string{"0.2.1", "0.2.9"}, []string{"0.2.0", "0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.2", "0.0.4", "0.1.0"}},
+		{"^0.0", []string{"0.0.0", "0.0.9"}, []string{"0.1.0"}},
+		{"^1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.2.2", "1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{">=1.0 <2", []string{"1.0.0", "1.5.0"}, []string{"0.9.0", "2.0.0"}},
+		{">=v1.2.0", []string{"1.2.0", "3.0.0"}, []string{"1.1.0"}},
+		{"^1.2.0-beta", []string{"1.2.0-beta", "1.2.0", "1.9.0"}, []string{"1.2.0-alpha", "2.0.0"}},
+		{"~1.2.0-beta", []string{"1.2.0-beta", "1.2.9"}, []string{"1.1.0", "1.3.0"}},
+		{"^0.2.0-beta", []string{"0.2.0-beta", "0.2.9"}, []string{"0.1.0", "0.3.0"}},
+		{"^0.0.3-beta", []string{"0.0.3-beta", "0.0.3"}, []string{"0.0.4", "0.1.0"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			versionRange, err := ParseVersionRange(test.spec)
+			if err != nil {
+				t.Fatalf("ParseVersionRange(%q) returned %s", test.spec, err)
+			}
+
+			for _, version := range test.accepted {
+				if !versionRange(semver.MustParse(version)) {
+					t.Errorf("ParseVersionRange(%q) rejects %s", test.spec, version)
+				}
+			}
+
+			for _, version := range test.rejected {
+				if versionRange(semver.MustParse(version)) {
+					t.Errorf("ParseVersionRange(%q) accepts %s", test.spec, version)
+				}
+			}
+		})
+	}
+}
+
+func TestParseVersionRangeInvalid(t *testing.T) {
+	for _, spec := range []string{"^", "^x.y", "~abc"} {
+		if _, err := ParseVersionRange(spec); err == nil {
+			t.Errorf("ParseVersionRange(%q) returned no error", spec)
+		}
+	}
+}
+
+func TestResolveAbbreviatedHash(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Commit until two commits share the first character of their hash.
+	seen := make(map[byte]plumbing.Hash)
+	var first, second plumbing.Hash
+	for i := 0; second.IsZero(); i++ {
+		hash, err := worktree.Commit(fmt.Sprintf("commit %d", i), &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(0, 0)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if other, ok := seen[hash.String()[0]]; ok {
+			first, second = other, hash
+		}
+
+		seen[hash.String()[0]] = hash
+	}
+
+	found, err := resolveAbbreviatedHash(repo, first.String()[:7])
+	if err != nil || found != first {
+		t.Errorf("resolveAbbreviatedHash(%q) = %s, %v, want %s", first.String()[:7], found, err, first)
+	}
+
+	if _, err := resolveAbbreviatedHash(repo, second.String()[:1]); err == nil {
+		t.Errorf("resolveAbbreviatedHash(%q) returned no error for an ambiguous ref", second.String()[:1])
+	}
+
+	if _, err := resolveAbbreviatedHash(repo, "ffffffffff"); err != plumbing.ErrReferenceNotFound {
+		t.Errorf("resolveAbbreviatedHash(%q) returned %v, want %v", "ffffffffff", err, plumbing.ErrReferenceNotFound)
+	}
+}
@@ -0,0 +1,180 @@
+package tmpl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// RegistryEntry is a template listed within a registry index.
+type RegistryEntry struct {
+	Name        string   `yaml:"name" json:"name"`
+	Description string   `yaml:"description" json:"description"`
+	Author      string   `yaml:"author" json:"author"`
+	Source      string   `yaml:"source" json:"source"`
+	Tags        []string `yaml:"tags" json:"tags"`
+	Versions    []string `yaml:"versions" json:"versions"`
+}
+
+// Registry is a template registry index, a YAML or JSON file listing
+// the templates available by name.
+type Registry struct {
+	Location  string          `yaml:"-" json:"-"`
+	Templates []RegistryEntry `yaml:"templates" json:"templates"`
+}
+
+// GetRegistries returns the locations of the registry indexes set
+// within `templates.registries`, as URLs or paths on disk.
+func GetRegistries() []string {
+	return viper.GetStringSlice("templates.registries")
+}
+
+// registryClient fetches remote registry indexes, giving up on
+// registries that do not respond rather than hanging every command that
+// resolves a template.
+var registryClient = &http.Client{Timeout: 30 * time.Second}
+
+// readRegistry reads the raw registry index at the location.
+func readRegistry(location string) ([]byte, error) {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		res, err := registryClient.Get(location)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetching %s returned %s", location, res.Status)
+		}
+
+		return ioutil.ReadAll(res.Body)
+	}
+
+	file, _ := homedir.Expand(strings.TrimPrefix(location, "file://"))
+
+	return ioutil.ReadFile(file)
+}
+
+// LoadRegistry loads the registry index at the location.
+func LoadRegistry(location string) (Registry, error) {
+	registry := Registry{Location: location}
+
+	data, err := readRegistry(location)
+	if err != nil {
+		return registry, err
+	}
+
+	if strings.EqualFold(filepath.Ext(location), ".json") {
+		err = json.Unmarshal(data, &registry)
+	} else {
+		err = yaml.Unmarshal(data, &registry)
+	}
+
+	if err != nil {
+		return registry, fmt.Errorf("parsing registry %s: %s", location, err)
+	}
+
+	return registry, nil
+}
+
+// LoadRegistries loads every configured registry index, returning the
+// templates they list. When registries list the same name, the
+// registry configured first wins.
+func LoadRegistries() ([]RegistryEntry, error) {
+	var entries []RegistryEntry
+	seen := make(map[string]bool)
+
+	for _, location := range GetRegistries() {
+		registry, err := LoadRegistry(location)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range registry.Templates {
+			if seen[entry.Name] {
+				continue
+			}
+
+			seen[entry.Name] = true
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// FindRegistryEntry returns the registry entry of the named template,
+// from the first configured registry listing it. Registries that cannot
+// be loaded are skipped, and reported by the returned error alongside
+// any entry found within the others.
+func FindRegistryEntry(name string) (RegistryEntry, bool, error) {
+	var failed []string
+
+	for _, location := range GetRegistries() {
+		registry, err := LoadRegistry(location)
+		if err != nil {
+			failed = append(failed, err.Error())
+
+			continue
+		}
+
+		for _, entry := range registry.Templates {
+			if entry.Name == name {
+				return entry, true, registryErrors(failed)
+			}
+		}
+	}
+
+	return RegistryEntry{}, false, registryErrors(failed)
+}
+
+// registryErrors combines the errors of the registries that could not
+// be loaded, or returns nil if every registry loaded.
+func registryErrors(failed []string) error {
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(failed, "; "))
+}
+
+// Matches returns true if the term is found within the entry's name,
+// description, author or tags, ignoring case.
+func (entry RegistryEntry) Matches(term string) bool {
+	term = strings.ToLower(term)
+	fields := append([]string{entry.Name, entry.Description, entry.Author}, entry.Tags...)
+
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), term) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SearchRegistries returns the registry entries matching the term.
+func SearchRegistries(term string) ([]RegistryEntry, error) {
+	entries, err := LoadRegistries()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []RegistryEntry
+	for _, entry := range entries {
+		if entry.Matches(term) {
+			matches = append(matches, entry)
+		}
+	}
+
+	return matches, nil
+}
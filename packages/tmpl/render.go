@@ -0,0 +1,125 @@
+package tmpl
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/lavrahq/cli/packages/funcs"
+)
+
+// FuncMap returns the function library available to the template's
+// files, along with `include`, which renders a partial from the
+// template repository.
+func (temp Template) FuncMap() template.FuncMap {
+	return temp.funcMapWithin(temp.TemplateDirectory.Path)
+}
+
+// funcMapWithin returns the function library with `include` rendering
+// partials from the directory, the repository of the template or of the
+// extended or included template the rendered file comes from.
+func (temp Template) funcMapWithin(dir string) template.FuncMap {
+	funcMap := funcs.Map()
+
+	funcMap["include"] = func(name string, data interface{}) (string, error) {
+		file := path.Join(dir, name)
+		if !withinDir(dir, file) {
+			return "", fmt.Errorf("the partial %s is outside of the template", name)
+		}
+
+		tmpl, err := template.New(path.Base(file)).Funcs(temp.funcMapWithin(dir)).ParseFiles(file)
+		if err != nil {
+			return "", err
+		}
+
+		var out bytes.Buffer
+		err = tmpl.Execute(&out, data)
+
+		return out.String(), err
+	}
+
+	return funcMap
+}
+
+// renderString renders the text as a Go template against the env.
+func renderString(temp Template, text string, env WhenEnvironment) (string, error) {
+	tmpl, err := template.New("").Funcs(temp.FuncMap()).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	err = tmpl.Execute(&out, env)
+
+	return out.String(), err
+}
+
+// renderFile renders the file as a Go template against the env, with
+// partials included from the template directory dir.
+func renderFile(temp Template, dir string, file string, env WhenEnvironment) ([]byte, error) {
+	tmpl, err := template.New(path.Base(file)).Funcs(temp.funcMapWithin(dir)).ParseFiles(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	err = tmpl.Execute(&out, env)
+
+	return out.Bytes(), err
+}
+
+// noValue is what text/template renders missing answers as.
+const noValue = "<no value>"
+
+// renderPath renders the slash separated path as a Go template against
+// the env. Missing answers, such as those skipped by their `when`,
+// render as empty. A path with a segment that renders to an empty
+// string is dropped, which is reported by returning false.
+func renderPath(temp Template, p string, env WhenEnvironment) (string, bool, error) {
+	if !strings.Contains(p, "{{") {
+		return p, true, nil
+	}
+
+	tmpl, err := template.New("").Option("missingkey=zero").Funcs(temp.FuncMap()).Parse(p)
+	if err != nil {
+		return "", false, err
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, env); err != nil {
+		return "", false, err
+	}
+
+	// Answers are held as interfaces, whose zero value still renders as
+	// `<no value>`.
+	rendered := strings.Replace(out.String(), noValue, "", -1)
+
+	// Only the slashes the path is given with are trimmed, so a leading
+	// or trailing segment that renders empty still drops the path.
+	segments := rendered
+	if strings.HasPrefix(p, "/") {
+		segments = strings.TrimPrefix(segments, "/")
+	}
+
+	if strings.HasSuffix(p, "/") {
+		segments = strings.TrimSuffix(segments, "/")
+	}
+
+	for _, segment := range strings.Split(segments, "/") {
+		if strings.TrimSpace(segment) == "" {
+			return "", false, nil
+		}
+	}
+
+	return rendered, true, nil
+}
+
+// withinDir returns true if the path is the directory or within it.
+func withinDir(dir string, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
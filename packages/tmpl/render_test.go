@@ -0,0 +1,46 @@
+package tmpl
+
+import (
+	"testing"
+
+	"github.com/lavrahq/cli/packages/prompt"
+)
+
+func TestRenderPath(t *testing.T) {
+	env := WhenEnvironment{
+		Answers: prompt.AnswerMap{"Name": "api", "Empty": ""},
+		Env:     map[string]string{},
+		Item:    prompt.AnswerMap{"Service": "web"},
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		rendered string
+		keep     bool
+	}{
+		{"plain path", "cmd/main.go", "cmd/main.go", true},
+		{"answer", "services/{{ .Answers.Name }}/main.go", "services/api/main.go", true},
+		{"item", "{{ .Item.Service }}.yml", "web.yml", true},
+		{"whole path", "{{ .Answers.Name }}/{{ .Item.Service }}", "api/web", true},
+		{"empty answer", "services/{{ .Answers.Empty }}/main.go", "", false},
+		{"skipped answer", "services/{{ .Answers.Skipped }}/main.go", "", false},
+		{"skipped item answer", "{{ .Item.Port }}/web.yml", "", false},
+		{"leading slash", "/{{ .Answers.Name }}/", "/api/", true},
+		{"missing env", "{{ .Env.MISSING }}/main.go", "", false},
+		{"conditional segment", "{{ if .Answers.Skipped }}docs{{ end }}/README.md", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rendered, keep, err := renderPath(Template{}, test.path, env)
+			if err != nil {
+				t.Fatalf("renderPath(%q) returned %s", test.path, err)
+			}
+
+			if rendered != test.rendered || keep != test.keep {
+				t.Errorf("renderPath(%q) = %q, %v, want %q, %v", test.path, rendered, keep, test.rendered, test.keep)
+			}
+		})
+	}
+}
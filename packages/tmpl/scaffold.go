@@ -0,0 +1,76 @@
+package tmpl
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	"gopkg.in/yaml.v2"
+)
+
+// scaffoldManifest returns the template.yml written for new templates.
+// It is marshalled rather than formatted, so that names YAML would read
+// differently, such as `yes` or `a: b`, are quoted.
+func scaffoldManifest(name string) ([]byte, error) {
+	question := yaml.MapSlice{
+		{Key: "name", Value: "Name"},
+		{Key: "type", Value: "Input"},
+		{Key: "prompt", Value: yaml.MapSlice{
+			{Key: "message", Value: "What is the name of the project?"},
+		}},
+		{Key: "validate", Value: yaml.MapSlice{
+			{Key: "required", Value: true},
+		}},
+		{Key: "transform", Value: "Slug"},
+	}
+
+	return yaml.Marshal(yaml.MapSlice{
+		{Key: "apiVersion", Value: APIVersion},
+		{Key: "name", Value: name},
+		{Key: "author", Value: ""},
+		{Key: "description", Value: ""},
+		{Key: "prompt", Value: yaml.MapSlice{
+			{Key: "questions", Value: []yaml.MapSlice{question}},
+		}},
+		{Key: "copy", Value: []yaml.MapSlice{
+			{{Key: "from", Value: "."}, {Key: "into", Value: "."}},
+		}},
+		{Key: "fill", Value: []yaml.MapSlice{
+			{{Key: "file", Value: "README.md"}},
+		}},
+	})
+}
+
+// scaffoldReadme is the example file filled by new templates.
+const scaffoldReadme = "# {{ .Answers.Name }}\n"
+
+// Scaffold writes a template skeleton into the directory: a manifest
+// asking for the project name, a template/ directory with a README to
+// fill, and a test case expecting the README to be filled.
+func Scaffold(dir string, name string) error {
+	manifest, err := scaffoldManifest(name)
+	if err != nil {
+		return err
+	}
+
+	files := map[string]string{
+		"template.yml":       string(manifest),
+		"template/README.md": scaffoldReadme,
+		path.Join(TestDirectory, "default", TestAnswersFile):               "Name: example\n",
+		path.Join(TestDirectory, "default", TestExpectedPath, "README.md"): "# example\n",
+	}
+
+	for file, content := range files {
+		file = path.Join(dir, file)
+
+		if err := os.MkdirAll(path.Dir(file), os.ModePerm); err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
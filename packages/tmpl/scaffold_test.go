@@ -0,0 +1,38 @@
+package tmpl
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestScaffoldName(t *testing.T) {
+	for _, name := range []string{"tpl", "yes", "a: b", "#tpl", "1.0", `"quoted"`} {
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "lavra-scaffold")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			if err := Scaffold(dir, name); err != nil {
+				t.Fatalf("Scaffold(%q) returned %s", name, err)
+			}
+
+			data, err := ioutil.ReadFile(path.Join(dir, "template.yml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			manifest, err := DecodeManifest(data, ".yml")
+			if err != nil {
+				t.Fatalf("DecodeManifest() returned %s", err)
+			}
+
+			if manifest.Name != name || len(manifest.Prompt.Questions) != 1 || len(manifest.Copy) != 1 || len(manifest.Fill) != 1 {
+				t.Errorf("Scaffold(%q) wrote %s", name, data)
+			}
+		})
+	}
+}
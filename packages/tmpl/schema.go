@@ -0,0 +1,224 @@
+package tmpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/hashicorp/hcl"
+	"github.com/lavrahq/cli/packages/funcs"
+	"github.com/lavrahq/cli/version"
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v2"
+)
+
+// APIVersion is the manifest schema version written by this CLI.
+// Manifests without an `apiVersion` predate schema versioning and are
+// read as LegacyAPIVersion.
+const (
+	APIVersion       = "v1"
+	LegacyAPIVersion = "v0"
+)
+
+// apiVersions lists every manifest schema version this CLI can read,
+// oldest first.
+var apiVersions = []string{LegacyAPIVersion, APIVersion}
+
+// manifestConverter converts a raw manifest from one schema version to
+// the next.
+type manifestConverter func(raw map[string]interface{}) error
+
+// manifestConverters convert raw manifests of each schema version into
+// the following version, so older manifests keep loading after the
+// schema changes.
+var manifestConverters = map[string]manifestConverter{
+	LegacyAPIVersion: convertLegacyManifest,
+}
+
+// convertLegacyManifest converts a manifest written before schema
+// versioning. The v1 schema is the schema those manifests were written
+// against, so only the version is set.
+func convertLegacyManifest(raw map[string]interface{}) error {
+	raw["apiVersion"] = "v1"
+
+	return nil
+}
+
+// apiVersionIndex returns the position of the schema version within
+// apiVersions, or -1 if this CLI does not know it.
+func apiVersionIndex(apiVersion string) int {
+	for i, known := range apiVersions {
+		if known == apiVersion {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// IsSupportedAPIVersion checks that this CLI can read manifests of the
+// given schema version.
+func IsSupportedAPIVersion(apiVersion string) bool {
+	return apiVersionIndex(apiVersion) != -1
+}
+
+// convertManifest converts the raw manifest up to the current schema
+// version.
+func convertManifest(raw map[string]interface{}) error {
+	apiVersion, _ := raw["apiVersion"].(string)
+	if apiVersion == "" {
+		apiVersion = LegacyAPIVersion
+	}
+
+	if !IsSupportedAPIVersion(apiVersion) {
+		return fmt.Errorf("the template uses apiVersion %s, which needs a newer CLI than this one (supporting up to %s), update the CLI with the `update` command", apiVersion, APIVersion)
+	}
+
+	for apiVersion != APIVersion {
+		if err := manifestConverters[apiVersion](raw); err != nil {
+			return fmt.Errorf("converting the manifest from apiVersion %s: %s", apiVersion, err)
+		}
+
+		apiVersion = raw["apiVersion"].(string)
+	}
+
+	return nil
+}
+
+// checkCLIVersion checks the running CLI is at least the manifest's
+// `minCliVersion`, given as a version such as `1.4` or `1.4.0`. Ranges
+// are rejected, since a range like `^1.4` would also reject newer major
+// versions. Development builds have no version and are never checked.
+func checkCLIVersion(manifest TemplateManifest) error {
+	if manifest.MinCLIVersion == "" || version.IsDevelopment() {
+		return nil
+	}
+
+	if IsVersionRange(manifest.MinCLIVersion) {
+		return fmt.Errorf("the template's minCliVersion `%s` must be a version, such as `1.4.0`, rather than a range", manifest.MinCLIVersion)
+	}
+
+	minimum, _, err := completeVersion(manifest.MinCLIVersion)
+	if err != nil {
+		return fmt.Errorf("the template's minCliVersion `%s` is invalid: %s", manifest.MinCLIVersion, err)
+	}
+
+	current, err := semver.ParseTolerant(version.Version)
+	if err != nil {
+		return nil
+	}
+
+	if current.LT(minimum) {
+		return fmt.Errorf("the template needs CLI version %s or newer, but this is %s, update the CLI with the `update` command", manifest.MinCLIVersion, version.Version)
+	}
+
+	return nil
+}
+
+// checkFuncsVersion checks the function library against the manifest's
+// `minFuncsVersion`.
+func checkFuncsVersion(manifest TemplateManifest) error {
+	if manifest.MinFuncsVersion > funcs.Version {
+		return fmt.Errorf("the template needs version %d of the template function library, but this CLI provides version %d, update the CLI with the `update` command", manifest.MinFuncsVersion, funcs.Version)
+	}
+
+	return nil
+}
+
+// hclObjectKeys are the manifest fields holding an object rather than a
+// list. HCL decodes every block as a list, so these are unwrapped.
+var hclObjectKeys = map[string]bool{
+	"prompt":   true,
+	"validate": true,
+	"hooks":    true,
+	"vars":     true,
+	"env":      true,
+	"messages": true,
+}
+
+// unwrapHCLBlocks unwraps the single blocks HCL decodes as lists, for
+// the fields of the manifest that hold an object.
+func unwrapHCLBlocks(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			field = unwrapHCLBlocks(field)
+
+			if blocks, ok := field.([]map[string]interface{}); ok && hclObjectKeys[key] && len(blocks) == 1 {
+				field = blocks[0]
+			}
+
+			v[key] = field
+		}
+	case []map[string]interface{}:
+		for i, item := range v {
+			v[i] = unwrapHCLBlocks(item).(map[string]interface{})
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = unwrapHCLBlocks(item)
+		}
+	}
+
+	return value
+}
+
+// decodeRawManifest decodes the manifest into a raw map, based on the
+// extension of its file.
+func decodeRawManifest(data []byte, ext string) (map[string]interface{}, error) {
+	raw := make(map[string]interface{})
+
+	switch strings.ToLower(strings.TrimPrefix(ext, ".")) {
+	case "json":
+		err := json.Unmarshal(data, &raw)
+
+		return raw, err
+	case "toml":
+		tree, err := toml.LoadBytes(data)
+		if err != nil {
+			return raw, err
+		}
+
+		return tree.ToMap(), nil
+	case "hcl":
+		err := hcl.Unmarshal(data, &raw)
+
+		return unwrapHCLBlocks(raw).(map[string]interface{}), err
+	}
+
+	err := yaml.Unmarshal(data, &raw)
+
+	return raw, err
+}
+
+// DecodeManifest decodes a template manifest written in the format of
+// the given file extension, converting manifests of older schema
+// versions and checking the template supports this CLI.
+func DecodeManifest(data []byte, ext string) (TemplateManifest, error) {
+	var manifest TemplateManifest
+
+	raw, err := decodeRawManifest(data, ext)
+	if err != nil {
+		return manifest, err
+	}
+
+	if err := convertManifest(raw); err != nil {
+		return manifest, err
+	}
+
+	converted, err := yaml.Marshal(raw)
+	if err != nil {
+		return manifest, err
+	}
+
+	if err := yaml.Unmarshal(converted, &manifest); err != nil {
+		return manifest, err
+	}
+
+	if err := checkCLIVersion(manifest); err != nil {
+		return manifest, err
+	}
+
+	return manifest, checkFuncsVersion(manifest)
+}
@@ -0,0 +1,165 @@
+package tmpl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnwrapHCLBlocks(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  interface{}
+	}{
+		{
+			name: "single object block",
+			value: map[string]interface{}{
+				"prompt": []map[string]interface{}{{"name": "p"}},
+			},
+			want: map[string]interface{}{
+				"prompt": map[string]interface{}{"name": "p"},
+			},
+		},
+		{
+			name: "list block",
+			value: map[string]interface{}{
+				"copy": []map[string]interface{}{{"from": "."}},
+			},
+			want: map[string]interface{}{
+				"copy": []map[string]interface{}{{"from": "."}},
+			},
+		},
+		{
+			name: "repeated object blocks",
+			value: map[string]interface{}{
+				"vars": []map[string]interface{}{{"a": 1}, {"b": 2}},
+			},
+			want: map[string]interface{}{
+				"vars": []map[string]interface{}{{"a": 1}, {"b": 2}},
+			},
+		},
+		{
+			name: "nested object blocks",
+			value: map[string]interface{}{
+				"prompt": []map[string]interface{}{{
+					"questions": []map[string]interface{}{{
+						"name":     "Name",
+						"validate": []map[string]interface{}{{"required": true}},
+					}},
+				}},
+			},
+			want: map[string]interface{}{
+				"prompt": map[string]interface{}{
+					"questions": []map[string]interface{}{{
+						"name":     "Name",
+						"validate": map[string]interface{}{"required": true},
+					}},
+				},
+			},
+		},
+		{
+			name: "blocks within lists",
+			value: map[string]interface{}{
+				"fill": []interface{}{
+					map[string]interface{}{"vars": []map[string]interface{}{{"a": 1}}},
+				},
+			},
+			want: map[string]interface{}{
+				"fill": []interface{}{
+					map[string]interface{}{"vars": map[string]interface{}{"a": 1}},
+				},
+			},
+		},
+		{
+			name:  "plain values",
+			value: map[string]interface{}{"name": "tpl", "include": []interface{}{"a", "b"}},
+			want:  map[string]interface{}{"name": "tpl", "include": []interface{}{"a", "b"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := unwrapHCLBlocks(test.value)
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("unwrapHCLBlocks() = %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDecodeManifestHCL(t *testing.T) {
+	data := []byte(`
+apiVersion = "v1"
+name = "tpl"
+
+prompt {
+  questions {
+    name = "Name"
+    type = "Input"
+  }
+
+  questions {
+    name = "Port"
+    type = "Number"
+  }
+}
+
+copy {
+  from = "."
+  into = "."
+}
+
+hooks {
+  postCopy {
+    run = "git init"
+  }
+}
+`)
+
+	manifest, err := DecodeManifest(data, ".hcl")
+	if err != nil {
+		t.Fatalf("DecodeManifest() returned %s", err)
+	}
+
+	if manifest.Name != "tpl" || len(manifest.Prompt.Questions) != 2 || len(manifest.Copy) != 1 {
+		t.Errorf("DecodeManifest() = %+v", manifest)
+	}
+
+	if len(manifest.Hooks.PostCopy) != 1 || manifest.Hooks.PostCopy[0].Run != "git init" {
+		t.Errorf("DecodeManifest() hooks = %+v", manifest.Hooks)
+	}
+}
+
+func TestDecodeManifestHCLValidationMessages(t *testing.T) {
+	data := []byte(`
+apiVersion = "v1"
+name = "tpl"
+
+prompt {
+  questions {
+    name = "Name"
+    type = "Input"
+
+    validate {
+      required = true
+      pattern = "^[a-z]+$"
+
+      messages {
+        pattern = "use lowercase letters"
+      }
+    }
+  }
+}
+`)
+
+	manifest, err := DecodeManifest(data, ".hcl")
+	if err != nil {
+		t.Fatalf("DecodeManifest() returned %s", err)
+	}
+
+	validation := manifest.Prompt.Questions[0].Validate
+	if !validation.Required || validation.Messages["pattern"] != "use lowercase letters" {
+		t.Errorf("DecodeManifest() validate = %+v", validation)
+	}
+}
@@ -0,0 +1,245 @@
+package tmpl
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/lavrahq/cli/packages/fs"
+	"github.com/lavrahq/cli/packages/prompt"
+)
+
+// Actions reported for each project file touched by an expansion or
+// upgrade.
+const (
+	ChangeCreated   = "created"
+	ChangeUpdated   = "updated"
+	ChangeUnchanged = "unchanged"
+	ChangeMerged    = "merged"
+	ChangeConflict  = "conflict"
+	ChangeDeleted   = "deleted"
+	ChangeKept      = "kept"
+)
+
+// FileChange describes what happens to a single project file, along
+// with the unified diff of the change. Resolution is how the file was
+// resolved when it conflicts with an existing project file.
+type FileChange struct {
+	File       string
+	Action     string
+	Diff       string
+	Resolution string
+}
+
+// listFiles returns the relative paths of every file within the root,
+// skipping git metadata and the expansion record.
+func listFiles(root string) (map[string]bool, error) {
+	files := make(map[string]bool)
+
+	err := filepath.Walk(root, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && (info.Name() == ".git" || info.Name() == RecordDirectory) {
+			return filepath.SkipDir
+		}
+
+		if !info.IsDir() {
+			rel, _ := filepath.Rel(root, file)
+			files[filepath.ToSlash(rel)] = true
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// readIfExists reads the file, returning nil if it does not exist.
+func readIfExists(file string) ([]byte, error) {
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return data, err
+}
+
+// Render expands the template at the given commit into the directory
+// using the provided answers, without asking anything. The commit is
+// exported into a scratch directory, so the cached checkout is left as
+// it is.
+func (temp Template) Render(commit string, dir fs.Directory, answers prompt.AnswerMap) (Template, error) {
+	scratchDir, err := ioutil.TempDir("", "lavra-render")
+	if err != nil {
+		return temp, err
+	}
+	defer os.RemoveAll(scratchDir)
+
+	if err := temp.Export(commit, scratchDir); err != nil {
+		return temp, err
+	}
+
+	temp.TemplateDirectory, _ = fs.MakeDirectory(scratchDir)
+	temp.Revision = commit
+
+	return temp.Expand(dir, answers)
+}
+
+// Expand expands the template as it is on disk into the directory
+// using the provided answers, without asking anything or running hooks.
+func (temp Template) Expand(dir fs.Directory, answers prompt.AnswerMap) (Template, error) {
+	temp = temp.LoadManifest()
+	temp.Directory = dir
+	temp.NoInput = true
+	temp.Conflicts = &ConflictLog{}
+	temp.Copied = &CopyLog{}
+
+	prompt.Answers[temp.Manifest.Name] = nil
+	if _, err := temp.Answer(answers); err != nil {
+		return temp, err
+	}
+
+	temp.Copy()
+	temp.Fill()
+
+	return temp, nil
+}
+
+// Upgrade three-way merges the difference between the base and next
+// renders of a template into the project. Files that only the template
+// changed are updated, files both sides changed are merged, and
+// overlapping changes are written with conflict markers. Files the
+// project changed or deleted are kept as they are when the template
+// deletes or changes them. Nothing is written when dryRun is set.
+func Upgrade(project, base, next fs.Directory, dryRun bool) ([]FileChange, error) {
+	var changes []FileChange
+
+	baseFiles, err := listFiles(base.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	nextFiles, err := listFiles(next.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range baseFiles {
+		names = append(names, name)
+	}
+
+	for name := range nextFiles {
+		if !baseFiles[name] {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		target := path.Join(project.Path, name)
+
+		baseData, err := readIfExists(path.Join(base.Path, name))
+		if err != nil {
+			return nil, err
+		}
+
+		nextData, err := readIfExists(path.Join(next.Path, name))
+		if err != nil {
+			return nil, err
+		}
+
+		ours, err := readIfExists(target)
+		if err != nil {
+			return nil, err
+		}
+
+		change := FileChange{File: name}
+		var result []byte
+
+		switch {
+		case !nextFiles[name]:
+			// Removed from the template, only delete untouched files.
+			if ours == nil {
+				continue
+			}
+
+			if !bytes.Equal(ours, baseData) {
+				change.Action = ChangeKept
+				changes = append(changes, change)
+
+				continue
+			}
+
+			change.Action = ChangeDeleted
+		case bytes.Equal(baseData, nextData) && baseFiles[name]:
+			continue
+		case ours == nil && baseFiles[name]:
+			// Deleted from the project but changed by the template, keep
+			// the deletion.
+			change.Action = ChangeKept
+			changes = append(changes, change)
+
+			continue
+		case ours == nil:
+			change.Action = ChangeCreated
+			result = nextData
+		case bytes.Equal(ours, nextData):
+			continue
+		case bytes.Equal(ours, baseData):
+			change.Action = ChangeUpdated
+			result = nextData
+		case bytes.IndexByte(ours, 0) != -1 || bytes.IndexByte(nextData, 0) != -1:
+			// Binary files can't be merged, keep the project's version.
+			change.Action = ChangeConflict
+			changes = append(changes, change)
+
+			continue
+		default:
+			merged, conflicted := Merge3(string(baseData), string(ours), string(nextData))
+
+			change.Action = ChangeMerged
+			if conflicted {
+				change.Action = ChangeConflict
+			}
+
+			result = []byte(merged)
+		}
+
+		change.Diff = UnifiedDiff("a/"+name, "b/"+name, string(ours), string(result))
+		changes = append(changes, change)
+
+		if dryRun {
+			continue
+		}
+
+		if change.Action == ChangeDeleted {
+			if err := os.Remove(target); err != nil {
+				return changes, err
+			}
+
+			continue
+		}
+
+		if err := os.MkdirAll(path.Dir(target), os.ModePerm); err != nil {
+			return changes, err
+		}
+
+		mode := os.FileMode(0644)
+		if info, err := os.Stat(path.Join(next.Path, name)); err == nil {
+			mode = info.Mode()
+		}
+
+		if err := ioutil.WriteFile(target, result, mode); err != nil {
+			return changes, err
+		}
+	}
+
+	return changes, nil
+}
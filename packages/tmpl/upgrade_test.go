@@ -0,0 +1,98 @@
+package tmpl
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/lavrahq/cli/packages/fs"
+)
+
+// writeTestFile writes the file within the dir, unless its content is
+// nil.
+func writeTestFile(t *testing.T, dir string, name string, content *string) {
+	if content == nil {
+		return
+	}
+
+	if err := ioutil.WriteFile(path.Join(dir, name), []byte(*content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testDirectory creates a temporary directory.
+func testDirectory(t *testing.T) fs.Directory {
+	dir, err := ioutil.TempDir("", "lavra-upgrade-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	directory, _ := fs.MakeDirectory(dir)
+
+	return directory
+}
+
+func TestUpgrade(t *testing.T) {
+	text := func(s string) *string { return &s }
+
+	tests := []struct {
+		name   string
+		base   *string
+		next   *string
+		ours   *string
+		action string
+		result *string
+	}{
+		{"unchanged", text("a\n"), text("a\n"), text("a\n"), "", text("a\n")},
+		{"updated", text("a\n"), text("b\n"), text("a\n"), ChangeUpdated, text("b\n")},
+		{"created", nil, text("b\n"), nil, ChangeCreated, text("b\n")},
+		{"already up to date", text("a\n"), text("b\n"), text("b\n"), "", text("b\n")},
+		{"merged", text("a\nb\nc\nd\n"), text("a\nb\nc\nD\n"), text("A\nb\nc\nd\n"), ChangeMerged, text("A\nb\nc\nD\n")},
+		{"conflict", text("a\n"), text("b\n"), text("c\n"), ChangeConflict, text("<<<<<<< project\nc\n=======\nb\n>>>>>>> template\n")},
+		{"deleted", text("a\n"), nil, text("a\n"), ChangeDeleted, nil},
+		{"changed then deleted by the template", text("a\n"), nil, text("b\n"), ChangeKept, text("b\n")},
+		{"deleted then changed by the template", text("a\n"), text("b\n"), nil, ChangeKept, nil},
+		{"deleted and unchanged by the template", text("a\n"), text("a\n"), nil, "", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			project, base, next := testDirectory(t), testDirectory(t), testDirectory(t)
+			defer os.RemoveAll(project.Path)
+			defer os.RemoveAll(base.Path)
+			defer os.RemoveAll(next.Path)
+
+			writeTestFile(t, base.Path, "file.txt", test.base)
+			writeTestFile(t, next.Path, "file.txt", test.next)
+			writeTestFile(t, project.Path, "file.txt", test.ours)
+
+			changes, err := Upgrade(project, base, next, false)
+			if err != nil {
+				t.Fatalf("Upgrade() returned %s", err)
+			}
+
+			action := ""
+			if len(changes) > 0 {
+				action = changes[0].Action
+			}
+
+			if action != test.action {
+				t.Errorf("Upgrade() action = %q, want %q", action, test.action)
+			}
+
+			data, err := readIfExists(path.Join(project.Path, "file.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.result == nil && data != nil {
+				t.Errorf("Upgrade() wrote %q, want no file", data)
+			}
+
+			if test.result != nil && (data == nil || string(data) != *test.result) {
+				t.Errorf("Upgrade() wrote %q, want %q", data, *test.result)
+			}
+		})
+	}
+}
@@ -24,6 +24,36 @@ func False(program string, env interface{}) bool {
 	return !True(program, env)
 }
 
+// Compile checks that the program compiles against the env, returning
+// the error instead of stopping command execution.
+func Compile(program string, env interface{}) error {
+	if ImplicitlyTrue(program) || ImplicitlyFalse(program) {
+		return nil
+	}
+
+	_, err := expr.Compile(program, expr.Env(env))
+
+	return err
+}
+
+// Check checks that the program provided evaluates to true, returning
+// any error instead of stopping command execution.
+func Check(program string, env interface{}) (bool, error) {
+	if ImplicitlyTrue(program) || ImplicitlyFalse(program) {
+		return ImplicitlyTrue(program), nil
+	}
+
+	eval, err := Eval(program, env)
+
+	return eval == true, err
+}
+
+// Eval returns the raw result of the evaluated program, returning any
+// error instead of stopping command execution.
+func Eval(program string, env interface{}) (interface{}, error) {
+	return expr.Eval(program, env)
+}
+
 // Evaluate returns the raw result of the evaluated program.
 func Evaluate(program string, env interface{}) interface{} {
 	if ImplicitlyTrue(program) {